@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxBagAttributes는 요청 하나가 속성 주머니에 모을 수 있는 속성 수의 상한입니다.
+// 반복문 안에서 속성을 더하는 코드가 스팬을 끝없이 키우지 않게 합니다.
+const maxBagAttributes = 32
+
+type attributeBagKey struct{}
+
+// attributeBag은 요청 동안 모은 서버 스팬 속성입니다. 핸들러가 고루틴을 띄워
+// 속성을 더할 수 있으므로 잠금으로 보호합니다.
+type attributeBag struct {
+	mu      sync.Mutex
+	attrs   []attribute.KeyValue
+	dropped int
+}
+
+// addSpanAttributes는 요청이 끝날 때 서버 스팬에 붙일 속성을 더합니다.
+// 깊은 곳의 코드가 스팬을 넘겨받지 않고도 요청 단위 속성을 남길 수 있습니다.
+// withAttributeBag 밖에서 호출하면 아무것도 하지 않습니다. 상한을 넘은 속성은 버리고
+// 그 수를 app.attributes.dropped로 기록합니다.
+func addSpanAttributes(ctx context.Context, kv ...attribute.KeyValue) {
+	bag, ok := ctx.Value(attributeBagKey{}).(*attributeBag)
+	if !ok {
+		return
+	}
+	bag.mu.Lock()
+	defer bag.mu.Unlock()
+	n := min(len(kv), maxBagAttributes-len(bag.attrs))
+	bag.attrs = append(bag.attrs, kv[:n]...)
+	bag.dropped += len(kv) - n
+}
+
+// withAttributeBag은 요청 컨텍스트에 속성 주머니를 넣고, 핸들러가 끝나면 모인 속성을
+// 서버 스팬에 한 번에 붙입니다. otelhttp 핸들러 안쪽에 있어야 합니다.
+func withAttributeBag(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bag := &attributeBag{}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), attributeBagKey{}, bag)))
+
+		bag.mu.Lock()
+		defer bag.mu.Unlock()
+		span := trace.SpanFromContext(r.Context())
+		span.SetAttributes(bag.attrs...)
+		if bag.dropped > 0 {
+			span.SetAttributes(attribute.Int("app.attributes.dropped", bag.dropped))
+		}
+	})
+}
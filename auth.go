@@ -0,0 +1,64 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// authHandler는 Basic 인증 또는 Bearer 토큰을 확인한 뒤 next를 호출합니다.
+// 둘 다 설정되어 있으면 어느 쪽이든 맞으면 통과합니다.
+// 인증 정보가 설정되지 않았으면 next를 그대로 반환합니다.
+func authHandler(cfg config, next http.Handler) http.Handler {
+	if !cfg.authEnabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(cfg, r) {
+			if cfg.AuthUsername != "" {
+				w.Header().Set("WWW-Authenticate", `Basic realm="dice"`)
+			} else {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+			}
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// protectPaths는 /metrics와 /admin/, /debug/ 아래 요청에만 인증을 요구합니다.
+// 앱 핸들러 전체를 감싸므로 HTTP 계측보다 바깥에 두면,
+// 인증에 실패한 스크레이프나 탐색 요청은 스팬을 남기지 않습니다.
+func protectPaths(cfg config, next http.Handler) http.Handler {
+	if !cfg.authEnabled() {
+		return next
+	}
+	protected := authHandler(cfg, next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metrics" || strings.HasPrefix(r.URL.Path, "/admin/") || strings.HasPrefix(r.URL.Path, "/debug/") {
+			protected.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorized는 요청의 인증 정보가 설정과 일치하는지 상수 시간 비교로 확인합니다.
+func authorized(cfg config, r *http.Request) bool {
+	if cfg.AuthToken != "" {
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && secureEqual(token, cfg.AuthToken) {
+			return true
+		}
+	}
+	if cfg.AuthUsername != "" {
+		if user, pass, ok := r.BasicAuth(); ok && secureEqual(user, cfg.AuthUsername) && secureEqual(pass, cfg.AuthPassword) {
+			return true
+		}
+	}
+	return false
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
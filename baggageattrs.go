@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// baggageSpanProcessor는 스팬이 시작될 때 부모 컨텍스트의 배기지 중 허용된 멤버를
+// 같은 키의 스팬 속성으로 복사합니다. 핸들러마다 배기지를 읽지 않아도 트레이스의 모든
+// 스팬이 배기지 값을 갖게 됩니다. 서버 스팬은 otelhttp가 배기지를 추출한 컨텍스트로
+// 시작되므로 서버 스팬에도 붙습니다.
+//
+// 배기지는 호출자가 마음대로 채울 수 있으므로 허용 목록 밖의 키는 복사하지 않습니다.
+type baggageSpanProcessor struct {
+	keys []string
+}
+
+func newBaggageSpanProcessor(keys []string) *baggageSpanProcessor {
+	return &baggageSpanProcessor{keys: keys}
+}
+
+func (p *baggageSpanProcessor) OnStart(parent context.Context, s trace.ReadWriteSpan) {
+	bag := baggage.FromContext(parent)
+	if bag.Len() == 0 {
+		return
+	}
+	for _, key := range p.keys {
+		if m := bag.Member(key); m.Key() != "" {
+			s.SetAttributes(attribute.String(key, m.Value()))
+		}
+	}
+}
+
+func (p *baggageSpanProcessor) OnEnd(trace.ReadOnlySpan)         {}
+func (p *baggageSpanProcessor) Shutdown(context.Context) error   { return nil }
+func (p *baggageSpanProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestBaggageMetricAttributes(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		baggage string
+		want    map[attribute.Key]string
+	}{
+		{"allowed member", []string{"tenant"}, "tenant=acme,secret=s3cr3t", map[attribute.Key]string{"tenant": "acme"}},
+		{"missing member", []string{"tenant"}, "secret=s3cr3t", nil},
+		{"allow list off", nil, "tenant=acme", nil},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.BaggageMetricAttributes = tt.allowed
+			// 테스트마다 다른 플레이어를 써서 이전 테스트의 데이터 포인트와 섞이지 않게 합니다.
+			player := "baggage" + string(rune('a'+i))
+			r := httptest.NewRequest(http.MethodGet, "/rolldice/"+player, nil)
+			r.Header.Set("baggage", tt.baggage)
+			w := serve(newTestHandler(cfg), r)
+			if w.Code != http.StatusOK {
+				t.Fatalf("상태 = %d, want 200", w.Code)
+			}
+			roll, _ := strconv.Atoi(strings.TrimSpace(w.Body.String()))
+
+			// 같은 테스트를 다시 돌려도 주사위 값까지 같으면 데이터 포인트가 하나로 합쳐집니다.
+			sets := counterAttributes(t, "dice.rolls", attribute.String("player", player), attribute.Int("roll.value", roll))
+			if len(sets) != 1 {
+				t.Fatalf("player=%s인 dice.rolls 데이터 포인트 %d개, want 1", player, len(sets))
+			}
+			for _, key := range []attribute.Key{"tenant", "secret"} {
+				got, ok := sets[0].Value(key)
+				want, wantOK := tt.want[key]
+				if ok != wantOK || (ok && got.AsString() != want) {
+					t.Errorf("%s 속성 = %q (있음 %v), want %q (있음 %v)", key, got.Emit(), ok, want, wantOK)
+				}
+			}
+		})
+	}
+}
+
+func TestBaggageSpanProcessor(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		baggage string
+		want    map[attribute.Key]string
+	}{
+		{"allowed members", []string{"tenant", "region"}, "tenant=acme,region=eu,secret=s3cr3t", map[attribute.Key]string{"tenant": "acme", "region": "eu"}},
+		{"missing member", []string{"tenant"}, "secret=s3cr3t", nil},
+		{"no baggage", []string{"tenant"}, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, exp := newRecordingTracerProvider(t, nil, sdktrace.WithSpanProcessor(newBaggageSpanProcessor(tt.allowed)))
+			bag, err := baggage.Parse(tt.baggage)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := baggage.ContextWithBaggage(context.Background(), bag)
+			ctx, parent := tp.Tracer("test").Start(ctx, "parent")
+			_, child := tp.Tracer("test").Start(ctx, "child")
+			child.End()
+			parent.End()
+
+			// 자식 스팬도 부모 컨텍스트에서 같은 배기지를 읽어 같은 속성을 갖습니다.
+			spans := exp.GetSpans()
+			if len(spans) != 2 {
+				t.Fatalf("스팬 %d개, want 2", len(spans))
+			}
+			for _, s := range spans {
+				for _, key := range []attribute.Key{"tenant", "region", "secret"} {
+					got, ok := spanAttr(s, key)
+					want, wantOK := tt.want[key]
+					if ok != wantOK || (ok && got.AsString() != want) {
+						t.Errorf("%s 스팬의 %s 속성 = %q (있음 %v), want %q (있음 %v)", s.Name, key, got.Emit(), ok, want, wantOK)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// maxBenchWork는 /rolldice/bench가 한 요청에 허용하는 작업량(SHA-256 반복 횟수)의 상한입니다.
+// 대략 수십 밀리초 분량으로, 엔드포인트가 노출되어도 한 요청이 CPU를 오래 붙잡지 못하게 합니다.
+const maxBenchWork = 100_000
+
+// bench는 부하 테스트용 엔드포인트입니다. work 쿼리 파라미터만큼 CPU 작업을 한 뒤
+// 일반 주사위 던지기와 똑같이 처리하므로, 모든 계측이 켜진 상태에서 텔레메트리
+// 파이프라인이 부하를 견디는지 확인할 수 있습니다. 작업은 "bench work" 스팬에
+// bench.work 속성과 함께 기록됩니다.
+func (h *diceHandler) bench(w http.ResponseWriter, r *http.Request) {
+	work := 0
+	if v := r.URL.Query().Get("work"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > maxBenchWork {
+			writeProblem(w, r, http.StatusBadRequest, fmt.Sprintf("work는 0~%d 사이의 정수여야 합니다: %q", maxBenchWork, v))
+			return
+		}
+		work = n
+	}
+
+	ctx, span := startSpan(r.Context(), "bench work")
+	span.SetAttributes(attribute.Int("bench.work", work))
+	sum := sha256.Sum256(nil)
+	for range work {
+		sum = sha256.Sum256(sum[:])
+	}
+	span.End()
+	addSpanAttributes(ctx, attribute.Int("bench.work", work))
+
+	h.rolldice(w, r)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"go.opentelemetry.io/otel/sdk/metric"
+)
+
+// bucketDropAggregation은 버킷 정보를 뺀 히스토그램 집계입니다. 지수 히스토그램은 버킷 수가
+// 모자라면 스케일을 낮춰 버킷을 합치므로, 남는 버킷은 1 미만과 1 이상의 값을 담는 최대 두 개뿐이고
+// 개수, 합계, 최솟값, 최댓값만 의미가 있습니다. 가장 낮은 스케일에서도 두 범위는 한 버킷에 담기지
+// 않으므로 MaxSize를 1로 두면 스케일 언더플로 에러가 납니다.
+//
+// 경계 없는 명시적 버킷 히스토그램은 쓸 수 없습니다. 계측기가 WithExplicitBucketBoundaries로
+// 권고한 경계가 리더의 집계 설정보다 우선하므로, 주사위 계측기처럼 경계를 지정한 계측기의
+// 버킷이 그대로 남습니다.
+var bucketDropAggregation = metric.AggregationBase2ExponentialHistogram{MaxSize: 2, MaxScale: 20}
+
+// bucketDropExporter는 이 익스포터를 쓰는 리더가 히스토그램을 bucketDropAggregation으로
+// 집계하게 합니다. 버킷별 개수가 빠지므로 대역폭이 좁은 엣지 환경에서 페이로드가 크게 줄지만,
+// 백엔드에서 백분위수(p50, p99 등)는 더 이상 계산할 수 없고 평균만 구할 수 있습니다.
+//
+// View는 측정 제공자의 모든 리더에 적용되므로 리더별 집계 선택(Aggregation)으로 바꿉니다.
+// 같은 제공자의 Prometheus 리더는 영향을 받지 않습니다. OTEL_SAMPLE_METRIC_VIEWS_FILE로 경계를 지정한
+// 히스토그램은 View의 집계가 리더 설정보다 우선하므로 버킷이 남습니다.
+type bucketDropExporter struct {
+	metric.Exporter
+}
+
+func (e bucketDropExporter) Aggregation(kind metric.InstrumentKind) metric.Aggregation {
+	if kind == metric.InstrumentKindHistogram {
+		return bucketDropAggregation
+	}
+	return e.Exporter.Aggregation(kind)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// cardinalityReader는 /admin/cardinality가 SDK의 현재 집계 상태를 읽는 데 쓰는 리더입니다.
+// 관리 모드에서만 측정 제공자에 등록됩니다. 누적(cumulative) 집계이므로 데이터 포인트 수가
+// 시작 이후 관측된 서로 다른 속성 조합의 수와 같습니다.
+var cardinalityReader = metric.NewManualReader()
+
+// instrumentCardinality는 계측기 하나의 속성 조합 수입니다.
+type instrumentCardinality struct {
+	Scope         string `json:"scope"`
+	Name          string `json:"name"`
+	AttributeSets int    `json:"attribute_sets"`
+}
+
+// serveCardinality는 계측기별 속성 조합 수를 많은 순서로 반환합니다.
+// player처럼 값 종류가 많은 속성이 백엔드에 부담을 주기 전에 찾아내는 데 씁니다.
+func serveCardinality(w http.ResponseWriter, r *http.Request) {
+	var rm metricdata.ResourceMetrics
+	if err := cardinalityReader.Collect(r.Context(), &rm); err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var report []instrumentCardinality
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			report = append(report, instrumentCardinality{
+				Scope:         sm.Scope.Name,
+				Name:          m.Name,
+				AttributeSets: dataPointCount(m.Data),
+			})
+		}
+	}
+	slices.SortFunc(report, func(a, b instrumentCardinality) int {
+		return cmp.Or(cmp.Compare(b.AttributeSets, a.AttributeSets), cmp.Compare(a.Name, b.Name))
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func dataPointCount(data metricdata.Aggregation) int {
+	switch d := data.(type) {
+	case metricdata.Sum[int64]:
+		return len(d.DataPoints)
+	case metricdata.Sum[float64]:
+		return len(d.DataPoints)
+	case metricdata.Gauge[int64]:
+		return len(d.DataPoints)
+	case metricdata.Gauge[float64]:
+		return len(d.DataPoints)
+	case metricdata.Histogram[int64]:
+		return len(d.DataPoints)
+	case metricdata.Histogram[float64]:
+		return len(d.DataPoints)
+	case metricdata.ExponentialHistogram[int64]:
+		return len(d.DataPoints)
+	case metricdata.ExponentialHistogram[float64]:
+		return len(d.DataPoints)
+	default:
+		return 0
+	}
+}
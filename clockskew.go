@@ -0,0 +1,49 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// durationClampProcessor는 끝난 시간이 시작 시간보다 앞선 스팬을 고친 뒤 다음 SpanProcessor로
+// 넘깁니다. SDK가 직접 잰 시간은 단조 시계를 쓰므로 벽시계가 뒤로 가도 음수가 되지 않지만,
+// trace.WithTimestamp로 시각을 넘기거나 재생한 스팬은 벽시계 점프의 영향을 그대로 받습니다.
+//
+// 고친 스팬은 끝난 시간을 시작 시간과 같게 하고(길이 0) span.duration.clamped=true 속성과
+// 원래 길이(span.duration.original, 나노초)를 남기며, 로그로도 알립니다.
+type durationClampProcessor struct {
+	trace.SpanProcessor
+}
+
+// newDurationClampProcessor는 next를 감싸 음수 길이의 스팬을 고칩니다.
+func newDurationClampProcessor(next trace.SpanProcessor) trace.SpanProcessor {
+	return &durationClampProcessor{SpanProcessor: next}
+}
+
+func (p *durationClampProcessor) OnEnd(s trace.ReadOnlySpan) {
+	d := s.EndTime().Sub(s.StartTime())
+	if d >= 0 {
+		p.SpanProcessor.OnEnd(s)
+		return
+	}
+	log.Printf("스팬 %q(%s)의 길이가 음수(%v)라 0으로 고칩니다. 시계가 뒤로 갔을 수 있습니다", s.Name(), s.SpanContext().SpanID(), d)
+	p.SpanProcessor.OnEnd(clampedSpan{
+		attributeOverrideSpan: attributeOverrideSpan{
+			ReadOnlySpan: s,
+			attrs: append(s.Attributes(),
+				attribute.Bool("span.duration.clamped", true),
+				attribute.Int64("span.duration.original", int64(d))),
+			dropped: s.DroppedAttributes(),
+		},
+	})
+}
+
+// clampedSpan은 끝난 시간을 시작 시간으로 바꿔 보여 주는 ReadOnlySpan입니다.
+type clampedSpan struct {
+	attributeOverrideSpan
+}
+
+func (s clampedSpan) EndTime() time.Time { return s.StartTime() }
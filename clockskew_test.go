@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestDurationClampProcessor(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		end         time.Time
+		wantClamped bool
+	}{
+		{"normal", start.Add(2 * time.Second), false},
+		{"zero length", start, false},
+		// 벽시계가 5초 뒤로 간 상황입니다.
+		{"clock jumped back", start.Add(-5 * time.Second), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, exp := newRecordingTracerProvider(t, newDurationClampProcessor)
+			_, span := tp.Tracer("test").Start(context.Background(), "op", trace.WithTimestamp(start))
+			span.SetAttributes(attribute.String("kept", "yes"))
+			span.End(trace.WithTimestamp(tt.end))
+
+			spans := exp.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("스팬 %d개, want 1", len(spans))
+			}
+			s := spans[0]
+			if d := s.EndTime.Sub(s.StartTime); d < 0 {
+				t.Errorf("길이 = %v, 음수입니다", d)
+			}
+			assertSpanAttr(t, s, "kept", attribute.StringValue("yes"))
+			_, clamped := spanAttr(s, "span.duration.clamped")
+			if clamped != tt.wantClamped {
+				t.Errorf("span.duration.clamped 있음 = %v, want %v", clamped, tt.wantClamped)
+			}
+			if !tt.wantClamped {
+				if !s.EndTime.Equal(tt.end) {
+					t.Errorf("끝난 시간 = %v, want %v", s.EndTime, tt.end)
+				}
+				return
+			}
+			if !s.EndTime.Equal(start) {
+				t.Errorf("고친 끝난 시간 = %v, want 시작 시간 %v", s.EndTime, start)
+			}
+			assertSpanAttr(t, s, "span.duration.original", attribute.Int64Value(int64(tt.end.Sub(start))))
+		})
+	}
+}
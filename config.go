@@ -0,0 +1,1043 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// config는 애플리케이션과 텔레메트리 파이프라인 설정을 담습니다.
+type config struct {
+	// ListenAddr는 앱 서버가 들을 주소입니다. 기본값은 ":8080"입니다.
+	ListenAddr string
+
+	// GRPCAddr는 gRPC DiceService 서버가 들을 주소입니다. 기본값은 ":9090"이고, 비워 두면
+	// gRPC 서버를 띄우지 않습니다.
+	GRPCAddr string
+
+	// ShutdownTimeout은 정상 종료 때 진행 중인 요청을 기다릴 최대 시간입니다.
+	// 지나면 남은 연결을 강제로 닫습니다. 0이면 끝날 때까지 기다립니다.
+	ShutdownTimeout time.Duration
+
+	// Demo가 켜져 있으면(기본값) 텔레메트리가 바로 보이도록 스팬 배치 간격을 1초, 메트릭
+	// 내보내기 간격을 3초로 줄입니다. 끄면 SDK 기본값(5초, 1분)을 씁니다.
+	Demo bool
+
+	// Strict가 켜져 있으면 기본값으로 조용히 대신하지 않고 시작을 거부합니다.
+	// 서비스 이름(OTEL_SERVICE_NAME 또는 OTEL_RESOURCE_ATTRIBUTES의 service.name)이 없거나,
+	// 익스포터를 명시하지 않았는데 OTLP 엔드포인트가 없어 stdout으로 내보내게 되면 에러입니다.
+	// 데모의 기본값은 끔입니다.
+	Strict bool
+
+	// MetricsShutdownTimeout은 별도 메트릭 서버(MetricsAddr)의 종료 제한 시간입니다.
+	// 지정하지 않으면 ShutdownTimeout과 같습니다.
+	MetricsShutdownTimeout time.Duration
+
+	// StdoutFormat은 stdout 추적, 메트릭, 로그 익스포터의 출력 형식입니다.
+	// "pretty"(기본값)는 사람이 읽기 좋게 들여쓰고, "compact"는 레코드마다 JSON 한 줄로
+	// 출력해 로그 수집기가 줄 단위로 처리할 수 있게 합니다.
+	StdoutFormat string
+
+	// TracesStdoutFormat은 stdout 추적 익스포터의 형식만 따로 정합니다.
+	// "pretty", "compact", "otlpjson"을 쓸 수 있고 비어 있으면 StdoutFormat을 따릅니다.
+	TracesStdoutFormat string
+
+	// SigquitDump가 켜져 있으면 SIGQUIT 수신 시 고루틴 프로파일과
+	// 텔레메트리 스냅샷을 DumpDir에 기록한 뒤 Go 기본 동작을 이어갑니다.
+	SigquitDump bool
+	DumpDir     string
+
+	// GzipLevel은 응답 gzip 압축 수준(1~9)입니다. 기본값 0은 압축하지 않습니다.
+	GzipLevel int
+
+	// TLSCertFile과 TLSKeyFile이 모두 설정되면 HTTPS로 서비스합니다.
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// ReplayFile이 설정되면 서버를 띄우지 않고 해당 otlpjson 파일의 스팬을
+	// 설정된 익스포터로 다시 내보낸 뒤 종료합니다(-replay 플래그).
+	ReplayFile string
+
+	// LogSampleRatios는 심각도 구간("trace", "debug", "info", "warn")별 로그 보존 비율입니다.
+	// 예: OTEL_SAMPLE_LOG_SAMPLING="debug=0.1,info=0.5". Error 이상은 항상 보존됩니다.
+	LogSampleRatios map[string]float64
+
+	// DebugExportSpans가 켜져 있으면 스팬 배치를 내보낼 때마다 그 내보내기를 나타내는 스팬을 남깁니다.
+	DebugExportSpans bool
+
+	// DebugShutdownSpan이 켜져 있으면 정상 종료를 일으킨 원인, 단계별 시간, 결과를
+	// "shutdown" 스팬으로 남깁니다.
+	DebugShutdownSpan bool
+
+	// MetricsAddr가 설정되면 /metrics를 앱 서버 대신 이 주소의 별도 서버에서 제공합니다.
+	MetricsAddr string
+
+	// Prometheus가 꺼져 있으면 측정 제공자에 Prometheus 리더를 붙이지 않고 /metrics도
+	// 제공하지 않습니다. 주기적 내보내기 리더는 OTEL_METRICS_EXPORTER=none으로 끕니다.
+	Prometheus bool
+
+	// NormalizePlayer가 켜져 있으면 플레이어 이름을 소문자로 바꾸고 앞뒤 공백을 없앤 뒤
+	// 스팬, 메트릭, 로그 속성으로 사용해 카디널리티를 줄입니다.
+	NormalizePlayer bool
+
+	// GoroutineMetrics가 켜져 있으면 경로별로 요청 전후의 고루틴 수 차이를 기록합니다.
+	GoroutineMetrics bool
+
+	// RuntimeMetrics가 켜져 있으면 고루틴 수, 메모리, GC 같은 Go 런타임 메트릭을 기록합니다.
+	// 메모리 통계는 읽을 때 잠깐 프로그램을 멈추므로 최대 RuntimeMetricsInterval마다 새로 읽고,
+	// 그 사이의 수집은 마지막 값을 다시 씁니다. 부하를 줄이려면 끄거나 간격을 늘립니다.
+	RuntimeMetrics         bool
+	RuntimeMetricsInterval time.Duration
+
+	// OTLPEndpoint가 설정되면 추적, 메트릭, 로그를 모두 OTLP로 내보냅니다.
+	// gRPC를 쓰는 익스포터는 하나의 gRPC 연결을 공유하고, OTLP/HTTP 익스포터는
+	// "<경로 접두사>/v1/traces" 등에 보냅니다.
+	OTLPEndpoint string
+	// OTLPInsecure는 스킴 없는 엔드포인트에 평문 연결을 사용할지 정합니다.
+	OTLPInsecure bool
+	// OTLPProtocol은 OTLP 전송 방식("grpc", "http/protobuf", "http/json")입니다.
+	// 비어 있으면 엔드포인트에 "https://host/otlp" 같은 경로 접두사가 있을 때 "http/protobuf",
+	// 없으면 "grpc"를 씁니다. gRPC는 요청 경로가 고정되어 있어 경로 접두사와 함께 쓸 수 없습니다.
+	// TracesProtocol, MetricsProtocol, LogsProtocol은 신호별로 이를 덮어씁니다.
+	OTLPProtocol    string
+	TracesProtocol  string
+	MetricsProtocol string
+	LogsProtocol    string
+
+	// RouteTimeout은 경로별 처리 제한 시간의 기본값입니다. 0이면 제한하지 않습니다.
+	// RouteTimeouts는 경로 패턴별로 이를 덮어씁니다.
+	// 예: OTEL_SAMPLE_ROUTE_TIMEOUTS="/rolldice/=500ms,/rolldice/{player}=2s"
+	RouteTimeout  time.Duration
+	RouteTimeouts map[string]time.Duration
+
+	// ForceExitOnSecondSignal이 켜져 있으면(기본값) 첫 번째 CTRL+C로 정상 종료를 시작한 뒤
+	// 두 번째 CTRL+C에서 정리를 기다리지 않고 즉시 종료합니다.
+	ForceExitOnSecondSignal bool
+
+	// RouteContentTypes는 쓰기 경로 패턴별로 받을 요청 본문 형식을 "|"로 나눠 지정합니다.
+	// 지정하지 않은 경로는 defaultRouteContentTypes를 따르며, 다른 형식은 415로 거절합니다.
+	// 예: OTEL_SAMPLE_ROUTE_CONTENT_TYPES="POST /admin/loglevel=application/x-www-form-urlencoded|multipart/form-data"
+	RouteContentTypes map[string]string
+
+	// SpanDropAttributes는 내보내기 전에 스팬에서 지울 속성 키 목록입니다.
+	// 예: OTEL_SAMPLE_SPAN_DROP_ATTRIBUTES="http.request.id,user_agent.original"
+	SpanDropAttributes []string
+
+	// SkipUserAgents에 있는 문자열이 User-Agent에 (대소문자 구분 없이) 들어 있는 요청은
+	// 스팬과 HTTP 메트릭을 전혀 만들지 않습니다. 표본 추출과 달리 기록 비용도 들지 않습니다.
+	// 예: OTEL_SAMPLE_SKIP_USER_AGENTS="Googlebot,kube-probe"
+	SkipUserAgents []string
+
+	// AttributeValueMaxLength는 내보내는 스팬의 문자열 속성 값 최대 길이(글자 수)입니다.
+	// 넘는 값은 잘라 "…"을 붙이고 app.attributes.truncated=true를 남깁니다. 0이면 자르지 않습니다.
+	AttributeValueMaxLength int
+
+	// FeatureFlags는 모든 요청 컨텍스트에 담을 기능 플래그 평가 결과입니다.
+	// FeatureFlagAttributes에 있는 플래그만 "feature.<이름>" 스팬 속성으로 기록합니다.
+	// 예: OTEL_SAMPLE_FEATURE_FLAGS="new_dice_algo=true",
+	//     OTEL_SAMPLE_FEATURE_FLAG_ATTRIBUTES="new_dice_algo"
+	FeatureFlags          map[string]string
+	FeatureFlagAttributes []string
+
+	// ListenAttempts는 주소가 사용 중(EADDRINUSE)일 때 바인딩을 시도할 최대 횟수입니다.
+	// 기본값 1은 재시도하지 않습니다. 재시도 간격은 ListenRetryDelay부터 두 배씩 늘어납니다.
+	ListenAttempts   int
+	ListenRetryDelay time.Duration
+
+	// DeploymentEnvironment는 deployment.environment 리소스 속성(예: "production", "staging")입니다.
+	// 비어 있으면 붙이지 않습니다. OTEL_RESOURCE_ATTRIBUTES와 리소스 속성 파일이 이 값을 덮어씁니다.
+	DeploymentEnvironment string
+
+	// ResourceAttributesFile은 리소스 속성을 "key=value" 줄로 담은 파일 경로입니다.
+	// OTEL_RESOURCE_ATTRIBUTES의 같은 키가 파일보다 우선합니다.
+	ResourceAttributesFile string
+
+	// DynamicAttributesFile이 설정되면 이 "key=value" 파일을 DynamicAttributesInterval마다
+	// 다시 읽어 새로 시작하는 스팬에 속성으로 붙입니다. 리소스 속성과 달리 실행 중에 바뀔 수
+	// 있는 값(예: 리더 여부)을 위한 것이며, 리소스에는 들어가지 않습니다.
+	DynamicAttributesFile     string
+	DynamicAttributesInterval time.Duration
+
+	// ResourceDetectors는 리소스에 속성을 더할 감지기 목록입니다
+	// ("host", "os", "process", "container"). 비어 있으면 감지기를 쓰지 않습니다.
+	ResourceDetectors []string
+
+	// ResourceDetectTimeout은 감지기마다 기다릴 최대 시간입니다. 감지기는 동시에 실행되며,
+	// 시간 안에 끝나지 않은 감지기는 건너뛰고 시작을 계속합니다. 0이면 무제한입니다.
+	ResourceDetectTimeout time.Duration
+
+	// LogBatchSize와 LogExportInterval은 로그 배치 프로세서가 내보내기 전에 모을
+	// 레코드 수와 최대 대기 시간입니다. 트래픽이 적은 서비스에서 값을 키우면 내보내기
+	// 횟수는 줄지만, 로그가 수집기에 도착하기까지 최대 LogExportInterval만큼 늦어지고
+	// 비정상 종료 시 잃는 레코드도 늘어납니다. 0이면 SDK 기본값을 씁니다.
+	LogBatchSize      int
+	LogExportInterval time.Duration
+
+	// AuthUsername/AuthPassword(Basic 인증)나 AuthToken(Bearer 토큰)이 설정되면
+	// /metrics와 /admin/ 아래 경로에 인증을 요구합니다. 기본값은 인증 없음입니다.
+	AuthUsername string
+	AuthPassword string
+	AuthToken    string
+
+	// BaggageMetricAttributes는 주사위 메트릭 속성으로 기록할 배기지 키 허용 목록입니다.
+	// 값 종류가 적은 키만 넣어야 합니다. 예: OTEL_SAMPLE_BAGGAGE_METRIC_ATTRIBUTES="tenant,region"
+	BaggageMetricAttributes []string
+
+	// BaggageSpanAttributes는 모든 스팬에 같은 키의 속성으로 복사할 배기지 키 허용 목록입니다.
+	// 예: OTEL_SAMPLE_BAGGAGE_SPAN_ATTRIBUTES="tenant,session.id"
+	BaggageSpanAttributes []string
+
+	// MaxHeaderBytes는 앱 서버가 받을 요청 헤더의 최대 크기(바이트)입니다.
+	// 0이면 Go 기본값(1MB)을 쓰며, 넘으면 431로 거절합니다.
+	MaxHeaderBytes int
+
+	// MetricsDropBuckets가 켜져 있으면 OTLP(또는 stdout)로 내보내는 리더가 히스토그램을
+	// 버킷을 최소로 줄인 지수 히스토그램으로 집계해 개수, 합계, 최솟값, 최댓값만 보냅니다.
+	// Prometheus 엔드포인트는 영향을 받지 않습니다. 버킷이 없으면 백분위수를 추정할 수 없으므로
+	// MetricsPercentiles와 함께 쓸 수 없습니다.
+	MetricsDropBuckets bool
+
+	// StartupDelay는 서버가 뜬 뒤 /readyz가 준비 상태를 알리기 전에 기다리는 시간입니다.
+	// 예열이 필요한 환경에서 콜드 스타트를 완화합니다. 기본값 0은 기다리지 않습니다.
+	StartupDelay time.Duration
+
+	// Admin이 켜져 있으면 /admin/ 아래의 운영용 엔드포인트(예: /admin/loglevel)와
+	// 텔레메트리 파이프라인 상태(/debug/otel)를 노출합니다.
+	// 인증을 함께 설정하는 것을 권장합니다.
+	Admin bool
+
+	// AdminTraces가 켜져 있으면 관리 모드에서 최근에 끝난 스팬(최대 maxRecentSpans개)을
+	// 메모리에 보관하고 /admin/traces로 보여 줍니다. 백엔드 없이 보는 개발용입니다.
+	AdminTraces bool
+
+	// MaxSpansPerTrace는 요청 하나가 만들 수 있는 자식 스팬 수의 상한입니다.
+	// 넘은 스팬은 만들지 않고 서버 스팬의 truncated_spans 속성으로 개수만 남깁니다.
+	// 0이면 제한하지 않습니다.
+	MaxSpansPerTrace int
+
+	// NATSURL이 설정되면 스팬 배치를 OTLP JSON으로 NATSSubject 주제에도 발행합니다.
+	NATSURL     string
+	NATSSubject string
+
+	// ClampSpanDurations가 켜져 있으면 시계가 뒤로 가서 끝난 시간이 시작 시간보다 앞선 스팬을
+	// 길이 0으로 고쳐 내보내고 로그를 남깁니다. SDK가 잰 시간은 단조 시계를 쓰므로 주로
+	// 시각을 직접 넘긴 스팬에 해당합니다.
+	ClampSpanDurations bool
+
+	// TraceBufferDir이 설정되면 내보내기에 실패한 스팬 배치를 이 디렉터리에 저장했다가
+	// 수집기가 돌아오면 다시 내보냅니다. 저장된 배치가 TraceBufferMaxBytes를 넘으면 가장
+	// 오래된 배치부터 버립니다. 자세한 손실 조건은 diskBufferExporter를 참고하세요.
+	TraceBufferDir      string
+	TraceBufferMaxBytes int64
+
+	// TraceExportTimeout은 스팬 배치 하나를 내보내는 데 허용하는 최대 시간입니다.
+	// 0이면 SDK 기본값(30초)을 씁니다.
+	TraceExportTimeout time.Duration
+
+	// VersusURL은 /rolldice/versus/{player}가 상대의 주사위를 받으러 호출할 서비스의 주소입니다
+	// (예: "http://dice-b:8080"). 비어 있으면 ListenAddr의 자기 자신을 호출합니다.
+	VersusURL string
+
+	// DatabaseURL은 던지기 기록을 남길 데이터베이스입니다. "postgres://" 또는 "postgresql://"로
+	// 시작하면 Postgres DSN으로, 그 밖에는 SQLite 파일 경로로 봅니다. 기본값은 "dice.db"이고,
+	// 비워 두면 기록을 남기지 않으며 /rolldice/history/{player}도 노출하지 않습니다.
+	DatabaseURL string
+
+	// RollStatsJobInterval이 0보다 크면 그 간격으로 누적 던지기 통계를 요약해 로그로 남기는
+	// 백그라운드 작업을 실행합니다(rollStatsJob 참고).
+	RollStatsJobInterval time.Duration
+
+	// DemoTrafficInterval이 0보다 크면 그 간격으로 자기 자신의 /rolldice를 호출해
+	// 데모용 텔레메트리를 계속 만듭니다(-demo-traffic 플래그). 운영 환경에서는 끄세요.
+	DemoTrafficInterval time.Duration
+
+	// SDKMetrics가 켜져 있으면 SDK 자체 상태(기록 중인 스팬 수, 신호별로 내보낸 스팬과
+	// 로그 수와 실패 수)를 otel.sdk.* 메트릭으로 기록합니다.
+	SDKMetrics bool
+
+	// MetricsPercentiles가 켜져 있으면 OTLP(또는 stdout)로 내보내는 히스토그램마다
+	// 버킷으로 추정한 p50, p90, p99 게이지를 함께 보냅니다.
+	MetricsPercentiles bool
+
+	// ExemplarReservoirSize가 0보다 크면 히스토그램이 속성 조합마다 보관할 예시 수를
+	// 이 값으로 고정합니다. 0이면 SDK 기본값(버킷마다 하나)을 씁니다.
+	ExemplarReservoirSize int
+
+	// MetricViewsFile은 메트릭 View(버킷 경계, 속성 필터, 이름 변경, 버리기)를 담은
+	// JSON 파일 경로입니다. 형식은 viewSpec을 참고하세요. 다시 빌드하지 않고 집계를 조정할 수 있습니다.
+	MetricViewsFile string
+
+	// ReadyRequiresOTLP가 켜져 있으면 OTLP 수집기에 연결되기 전까지 /readyz가 503을 반환합니다.
+	// 텔레메트리 내보내기가 필수인 환경에서 내보낼 수 없는 파드로 트래픽이 가지 않게 합니다.
+	ReadyRequiresOTLP bool
+
+	// TracesExporter와 MetricsExporter는 추적과 메트릭을 내보낼 대상입니다("stdout", "otlp").
+	// 비어 있으면 OTLP 엔드포인트가 있을 때 "otlp", 없으면 "stdout"을 씁니다.
+	// MetricsExporter가 "none"이면 주기적 내보내기 리더 없이 Prometheus로만 제공합니다.
+	TracesExporter  string
+	MetricsExporter string
+
+	// LogsExporters는 로그를 내보낼 대상 목록입니다("stdout", "otlp").
+	// 예: OTEL_LOGS_EXPORTER="otlp,stdout"은 수집기와 stdout에 동시에 내보냅니다.
+	// 비어 있으면 OTLP 엔드포인트가 있을 때 "otlp", 없으면 "stdout"을 씁니다.
+	LogsExporters []string
+
+	// IDSeed가 0이 아니면 이 시드로 트레이스와 스팬 ID를 결정적으로 만듭니다.
+	// OTEL_TRACES_SAMPLER=traceidratio와 함께 쓰면 표본 추출 결과를 재현할 수 있습니다.
+	// 테스트 전용이며, 운영 환경에서는 ID가 서비스 사이에 충돌할 수 있습니다.
+	IDSeed int64
+
+	// DiceSeed가 0이 아니면 crypto/rand 대신 이 시드에서 파생한 난수로 주사위를 던지고,
+	// 던지기마다 쓴 시드를 roll 스팬에 기록합니다. 테스트와 재현용이며 운영 환경에서 쓰면 안 됩니다.
+	DiceSeed int64
+
+	// Bench가 켜져 있으면 부하 테스트용 /rolldice/bench 엔드포인트를 노출합니다.
+	Bench bool
+
+	// ClientTracePhases가 켜져 있으면 계측된 HTTP 클라이언트가 DNS, 연결, TLS,
+	// 첫 바이트 등 요청 단계별 하위 스팬을 만듭니다.
+	ClientTracePhases bool
+
+	// RollSeverityByValue가 켜져 있으면 던지기 로그를 값에 따라 1은 Warn, 6은 Info,
+	// 나머지는 Debug로 남깁니다. 끄면(기본값) 모두 Info입니다.
+	RollSeverityByValue bool
+
+	// ServerSpanName은 서버 스팬 이름을 정하는 방식입니다.
+	// "route"(기본값)는 "GET /rolldice/{player}"처럼 메서드와 일치한 경로 패턴을,
+	// "operation"은 otelhttp 연산 이름("/")을 그대로 씁니다.
+	ServerSpanName string
+
+	// RoutePatternAttribute가 켜져 있으면 서버 스팬에 일치한 ServeMux 패턴을
+	// server.route.pattern 속성으로 남깁니다.
+	RoutePatternAttribute bool
+
+	// TenantAttributesFile이 설정되면 TenantHeader 요청 헤더의 테넌트 ID마다 파일에 적힌
+	// 속성(예: service.namespace)을 그 요청의 모든 스팬에 붙입니다. 리소스는 프로세스 전체에
+	// 하나뿐이라 요청마다 바꿀 수 없으므로 스팬 속성으로 대신합니다. 형식은 newTenantProcessor를 참고하세요.
+	TenantAttributesFile string
+	TenantHeader         string
+
+	// BaggagePropagation을 끄면(기본값은 켬) 배기지를 전파하지 않고 트레이스 컨텍스트만 전파합니다.
+	// 들어오는 배기지도 읽지 않으므로 BaggageMetricAttributes와 BaggageSpanAttributes는 효과가 없어집니다.
+	BaggagePropagation bool
+
+	// DatadogPropagation이 켜져 있으면 x-datadog-* 헤더도 읽고 써서 Datadog으로 계측된
+	// 서비스의 추적과 표본 추출 우선순위를 이어 받습니다.
+	DatadogPropagation bool
+
+	// TrustInboundDebug가 켜져 있으면 들어오는 tracestate의 debug=1 항목을 따라 그 요청을
+	// 항상 표본 추출합니다. 외부 호출자가 추적 양을 늘릴 수 있으므로 기본값은 끔입니다
+	// (debugSampler 참고). 이 서비스 안에서 표시한 디버그 추적은 설정과 관계없이 따릅니다.
+	TrustInboundDebug bool
+
+	// DemoTrafficDebug가 켜져 있으면 합성 트래픽 요청을 디버그 추적으로 표시합니다.
+	DemoTrafficDebug bool
+
+	// TracesSampler는 표본 추출기 이름이고(OTEL_TRACES_SAMPLER, samplers 참고), TracesSamplerArg는
+	// 그 인자입니다(OTEL_TRACES_SAMPLER_ARG). 비율 표본 추출기는 0~1 사이의 비율(비어 있으면 1),
+	// ratelimited는 초당 트레이스 수(비어 있으면 10)를 받습니다.
+	// 기본값은 OpenTelemetry 관례를 따라 "parentbased_always_on"입니다.
+	TracesSampler    string
+	TracesSamplerArg string
+
+	// TracesProcessor와 LogsProcessor는 신호별 프로세서 종류입니다("batch", "simple").
+	// "batch"(기본값)는 모아서 내보내고, "simple"은 스팬이 끝나거나 로그를 남길 때마다
+	// 그 자리에서 동기적으로 내보내므로 개발 중 바로 확인할 수 있지만 요청이 느려집니다.
+	TracesProcessor string
+	LogsProcessor   string
+
+	// PrometheusRegisterer는 Prometheus 익스포터를 등록할 레지스트리입니다.
+	// 환경 변수로는 설정하지 않으며, 다른 애플리케이션에 포함할 때 코드에서 지정합니다.
+	// nil이면 기본 레지스트리를 사용합니다.
+	PrometheusRegisterer prometheus.Registerer
+
+	// Offline이면 환경 변수의 익스포터 설정과 관계없이 세 신호 모두 출력을 버리는 익스포터를
+	// 쓰고, OTLP 연결과 NATS 발행기를 만들지 않습니다. 수집기가 없는 CI 테스트에서
+	// setupOTelSDK를 네트워크 없이 부를 때 코드에서 켭니다. 환경 변수로는 설정하지 않습니다.
+	Offline bool
+}
+
+// defaultConfig는 환경 변수가 없을 때 사용할 기본 설정을 반환합니다.
+func defaultConfig() config {
+	return config{
+		ListenAddr:      ":8080",
+		GRPCAddr:        ":9090",
+		DatabaseURL:     "dice.db",
+		ShutdownTimeout: 10 * time.Second,
+		// 음수는 "ShutdownTimeout을 따름"을 뜻하며 loadConfig에서 채워집니다.
+		MetricsShutdownTimeout: -1,
+		Prometheus:             true,
+		RuntimeMetrics:         true,
+		RuntimeMetricsInterval: 15 * time.Second,
+
+		StdoutFormat:    "pretty",
+		DumpDir:         os.TempDir(),
+		ServerSpanName:  "route",
+		TracesSampler:   "parentbased_always_on",
+		TracesProcessor: "batch",
+		LogsProcessor:   "batch",
+
+		Demo:                    true,
+		ForceExitOnSecondSignal: true,
+		BaggagePropagation:      true,
+
+		NATSSubject:         "otel.spans",
+		TraceBufferMaxBytes: 64 << 20,
+		TenantHeader:        "X-Tenant-ID",
+
+		AttributeValueMaxLength: 4096,
+
+		ListenAttempts:   1,
+		ListenRetryDelay: 500 * time.Millisecond,
+
+		DynamicAttributesInterval: 30 * time.Second,
+		ResourceDetectTimeout:     5 * time.Second,
+	}
+}
+
+// loadConfig는 기본 설정에 설정 파일(OTEL_SAMPLE_CONFIG_FILE)을, 그 위에 환경 변수와
+// 명령줄 플래그를 차례로 덮어써 설정을 만듭니다.
+func loadConfig(args []string) (config, error) {
+	cfg := defaultConfig()
+
+	var env envLoader
+	if path := os.Getenv("OTEL_SAMPLE_CONFIG_FILE"); path != "" {
+		file, err := readConfigFile(path)
+		if err != nil {
+			return cfg, err
+		}
+		env.file = file
+	}
+	env.string("OTEL_SAMPLE_LISTEN_ADDR", &cfg.ListenAddr)
+	env.string("OTEL_SAMPLE_GRPC_ADDR", &cfg.GRPCAddr)
+	env.string("OTEL_SAMPLE_VERSUS_URL", &cfg.VersusURL)
+	env.string("OTEL_SAMPLE_DATABASE_URL", &cfg.DatabaseURL)
+	env.duration("OTEL_SAMPLE_ROLL_STATS_JOB_INTERVAL", &cfg.RollStatsJobInterval)
+	env.bool("OTEL_SAMPLE_DEMO", &cfg.Demo)
+	env.bool("OTEL_SAMPLE_STRICT", &cfg.Strict)
+	env.duration("OTEL_SAMPLE_SHUTDOWN_TIMEOUT", &cfg.ShutdownTimeout)
+	env.duration("OTEL_SAMPLE_METRICS_SHUTDOWN_TIMEOUT", &cfg.MetricsShutdownTimeout)
+	env.string("OTEL_SAMPLE_STDOUT_FORMAT", &cfg.StdoutFormat)
+	env.string("OTEL_SAMPLE_TRACES_STDOUT_FORMAT", &cfg.TracesStdoutFormat)
+	env.bool("OTEL_SAMPLE_SIGQUIT_DUMP", &cfg.SigquitDump)
+	env.string("OTEL_SAMPLE_DUMP_DIR", &cfg.DumpDir)
+	env.int("OTEL_SAMPLE_GZIP_LEVEL", &cfg.GzipLevel)
+	env.string("OTEL_SAMPLE_TLS_CERT_FILE", &cfg.TLSCertFile)
+	env.string("OTEL_SAMPLE_TLS_KEY_FILE", &cfg.TLSKeyFile)
+	env.floatMap("OTEL_SAMPLE_LOG_SAMPLING", &cfg.LogSampleRatios)
+	env.bool("OTEL_SAMPLE_DEBUG_EXPORT_SPANS", &cfg.DebugExportSpans)
+	env.bool("OTEL_SAMPLE_DEBUG_SHUTDOWN_SPAN", &cfg.DebugShutdownSpan)
+	env.string("OTEL_SAMPLE_METRICS_ADDR", &cfg.MetricsAddr)
+	env.bool("OTEL_SAMPLE_PROMETHEUS", &cfg.Prometheus)
+	env.bool("OTEL_SAMPLE_NORMALIZE_PLAYER", &cfg.NormalizePlayer)
+	env.bool("OTEL_SAMPLE_GOROUTINE_METRICS", &cfg.GoroutineMetrics)
+	env.bool("OTEL_SAMPLE_RUNTIME_METRICS", &cfg.RuntimeMetrics)
+	env.duration("OTEL_SAMPLE_RUNTIME_METRICS_INTERVAL", &cfg.RuntimeMetricsInterval)
+	env.string("OTEL_EXPORTER_OTLP_ENDPOINT", &cfg.OTLPEndpoint)
+	env.bool("OTEL_EXPORTER_OTLP_INSECURE", &cfg.OTLPInsecure)
+	env.string("OTEL_EXPORTER_OTLP_PROTOCOL", &cfg.OTLPProtocol)
+	env.string("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", &cfg.TracesProtocol)
+	env.string("OTEL_EXPORTER_OTLP_METRICS_PROTOCOL", &cfg.MetricsProtocol)
+	env.string("OTEL_EXPORTER_OTLP_LOGS_PROTOCOL", &cfg.LogsProtocol)
+	env.duration("OTEL_SAMPLE_ROUTE_TIMEOUT", &cfg.RouteTimeout)
+	env.durationMap("OTEL_SAMPLE_ROUTE_TIMEOUTS", &cfg.RouteTimeouts)
+	env.bool("OTEL_SAMPLE_FORCE_EXIT_ON_SECOND_SIGNAL", &cfg.ForceExitOnSecondSignal)
+	env.stringMap("OTEL_SAMPLE_ROUTE_CONTENT_TYPES", &cfg.RouteContentTypes)
+	env.stringList("OTEL_SAMPLE_SPAN_DROP_ATTRIBUTES", &cfg.SpanDropAttributes)
+	env.int("OTEL_SAMPLE_ATTRIBUTE_VALUE_MAX_LENGTH", &cfg.AttributeValueMaxLength)
+	env.stringList("OTEL_SAMPLE_SKIP_USER_AGENTS", &cfg.SkipUserAgents)
+	env.stringMap("OTEL_SAMPLE_FEATURE_FLAGS", &cfg.FeatureFlags)
+	env.stringList("OTEL_SAMPLE_FEATURE_FLAG_ATTRIBUTES", &cfg.FeatureFlagAttributes)
+	env.int("OTEL_SAMPLE_LISTEN_ATTEMPTS", &cfg.ListenAttempts)
+	env.duration("OTEL_SAMPLE_LISTEN_RETRY_DELAY", &cfg.ListenRetryDelay)
+	env.string("OTEL_SAMPLE_RESOURCE_ATTRIBUTES_FILE", &cfg.ResourceAttributesFile)
+	env.string("OTEL_SAMPLE_DEPLOYMENT_ENVIRONMENT", &cfg.DeploymentEnvironment)
+	env.stringList("OTEL_SAMPLE_RESOURCE_DETECTORS", &cfg.ResourceDetectors)
+	env.duration("OTEL_SAMPLE_RESOURCE_DETECT_TIMEOUT", &cfg.ResourceDetectTimeout)
+	env.string("OTEL_SAMPLE_DYNAMIC_ATTRIBUTES_FILE", &cfg.DynamicAttributesFile)
+	env.duration("OTEL_SAMPLE_DYNAMIC_ATTRIBUTES_INTERVAL", &cfg.DynamicAttributesInterval)
+	env.int("OTEL_SAMPLE_LOG_BATCH_SIZE", &cfg.LogBatchSize)
+	env.duration("OTEL_SAMPLE_LOG_EXPORT_INTERVAL", &cfg.LogExportInterval)
+	env.string("OTEL_SAMPLE_AUTH_USERNAME", &cfg.AuthUsername)
+	env.string("OTEL_SAMPLE_AUTH_PASSWORD", &cfg.AuthPassword)
+	env.string("OTEL_SAMPLE_AUTH_TOKEN", &cfg.AuthToken)
+	env.stringList("OTEL_SAMPLE_BAGGAGE_METRIC_ATTRIBUTES", &cfg.BaggageMetricAttributes)
+	env.stringList("OTEL_SAMPLE_BAGGAGE_SPAN_ATTRIBUTES", &cfg.BaggageSpanAttributes)
+	env.int("OTEL_SAMPLE_MAX_HEADER_BYTES", &cfg.MaxHeaderBytes)
+	env.bool("OTEL_SAMPLE_METRICS_DROP_BUCKETS", &cfg.MetricsDropBuckets)
+	env.duration("OTEL_SAMPLE_STARTUP_DELAY", &cfg.StartupDelay)
+	env.bool("OTEL_SAMPLE_ADMIN", &cfg.Admin)
+	env.bool("OTEL_SAMPLE_ADMIN_TRACES", &cfg.AdminTraces)
+	env.int("OTEL_SAMPLE_MAX_SPANS_PER_TRACE", &cfg.MaxSpansPerTrace)
+	env.string("OTEL_SAMPLE_NATS_URL", &cfg.NATSURL)
+	env.string("OTEL_SAMPLE_NATS_SUBJECT", &cfg.NATSSubject)
+	env.duration("OTEL_SAMPLE_TRACE_EXPORT_TIMEOUT", &cfg.TraceExportTimeout)
+	env.string("OTEL_SAMPLE_TRACE_BUFFER_DIR", &cfg.TraceBufferDir)
+	env.bool("OTEL_SAMPLE_CLAMP_SPAN_DURATIONS", &cfg.ClampSpanDurations)
+	env.int64("OTEL_SAMPLE_TRACE_BUFFER_MAX_BYTES", &cfg.TraceBufferMaxBytes)
+	env.bool("OTEL_SAMPLE_METRICS_PERCENTILES", &cfg.MetricsPercentiles)
+	env.bool("OTEL_SAMPLE_SDK_METRICS", &cfg.SDKMetrics)
+	env.int("OTEL_SAMPLE_EXEMPLAR_RESERVOIR_SIZE", &cfg.ExemplarReservoirSize)
+	env.string("OTEL_SAMPLE_METRIC_VIEWS_FILE", &cfg.MetricViewsFile)
+	env.string("OTEL_SAMPLE_TENANT_ATTRIBUTES_FILE", &cfg.TenantAttributesFile)
+	env.string("OTEL_SAMPLE_TENANT_HEADER", &cfg.TenantHeader)
+	env.bool("OTEL_SAMPLE_READY_REQUIRES_OTLP", &cfg.ReadyRequiresOTLP)
+	env.string("OTEL_TRACES_EXPORTER", &cfg.TracesExporter)
+	env.string("OTEL_METRICS_EXPORTER", &cfg.MetricsExporter)
+	env.stringList("OTEL_LOGS_EXPORTER", &cfg.LogsExporters)
+	env.int64("OTEL_SAMPLE_ID_SEED", &cfg.IDSeed)
+	env.int64("OTEL_SAMPLE_DICE_SEED", &cfg.DiceSeed)
+	env.bool("OTEL_SAMPLE_CLIENT_TRACE_PHASES", &cfg.ClientTracePhases)
+	env.string("OTEL_SAMPLE_SERVER_SPAN_NAME", &cfg.ServerSpanName)
+	env.bool("OTEL_SAMPLE_ROUTE_PATTERN_ATTRIBUTE", &cfg.RoutePatternAttribute)
+	env.bool("OTEL_SAMPLE_BENCH", &cfg.Bench)
+	env.bool("OTEL_SAMPLE_ROLL_SEVERITY_BY_VALUE", &cfg.RollSeverityByValue)
+	env.bool("OTEL_SAMPLE_BAGGAGE_PROPAGATION", &cfg.BaggagePropagation)
+	env.bool("OTEL_SAMPLE_DATADOG_PROPAGATION", &cfg.DatadogPropagation)
+	env.string("OTEL_TRACES_SAMPLER", &cfg.TracesSampler)
+	env.bool("OTEL_SAMPLE_TRUST_INBOUND_DEBUG", &cfg.TrustInboundDebug)
+	env.bool("OTEL_SAMPLE_DEMO_TRAFFIC_DEBUG", &cfg.DemoTrafficDebug)
+	env.string("OTEL_TRACES_SAMPLER_ARG", &cfg.TracesSamplerArg)
+	env.string("OTEL_SAMPLE_TRACES_PROCESSOR", &cfg.TracesProcessor)
+	env.string("OTEL_SAMPLE_LOGS_PROCESSOR", &cfg.LogsProcessor)
+	if err := env.err(); err != nil {
+		return cfg, err
+	}
+
+	fs := flag.NewFlagSet("dice", flag.ContinueOnError)
+	fs.StringVar(&cfg.ListenAddr, "addr", cfg.ListenAddr, "앱 서버가 들을 주소")
+	fs.StringVar(&cfg.GRPCAddr, "grpc-addr", cfg.GRPCAddr, "gRPC 서버가 들을 주소(비우면 끔)")
+	fs.BoolVar(&cfg.Strict, "strict", cfg.Strict, "서비스 이름이나 OTLP 엔드포인트가 없으면 시작하지 않습니다")
+	fs.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", cfg.ShutdownTimeout, "정상 종료 때 진행 중인 요청을 기다릴 최대 시간(0이면 무제한)")
+	fs.DurationVar(&cfg.MetricsShutdownTimeout, "metrics-shutdown-timeout", cfg.MetricsShutdownTimeout, "별도 메트릭 서버의 종료 제한 시간(음수면 -shutdown-timeout을 따름)")
+	fs.StringVar(&cfg.ReplayFile, "replay", cfg.ReplayFile, "otlpjson 스팬 파일을 재생하고 종료합니다")
+	fs.DurationVar(&cfg.DemoTrafficInterval, "demo-traffic", cfg.DemoTrafficInterval, "이 간격으로 합성 트래픽을 보냅니다(0이면 끔)")
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+	if cfg.MetricsShutdownTimeout < 0 {
+		cfg.MetricsShutdownTimeout = cfg.ShutdownTimeout
+	}
+
+	return cfg, errors.Join(cfg.validate(), cfg.validateStrict())
+}
+
+// validate는 설정 값이 허용 범위에 있는지 확인합니다.
+func (c config) validate() error {
+	var errs []error
+	switch c.StdoutFormat {
+	case "pretty", "compact":
+	default:
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_STDOUT_FORMAT: 알 수 없는 형식 %q", c.StdoutFormat))
+	}
+	switch c.TracesStdoutFormat {
+	case "", "pretty", "compact", "otlpjson":
+	default:
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_TRACES_STDOUT_FORMAT: 알 수 없는 형식 %q", c.TracesStdoutFormat))
+	}
+	switch c.ServerSpanName {
+	case "route", "operation":
+	default:
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_SERVER_SPAN_NAME: 알 수 없는 방식 %q", c.ServerSpanName))
+	}
+	for _, name := range c.ResourceDetectors {
+		if _, ok := resourceDetectors[name]; !ok {
+			errs = append(errs, fmt.Errorf("OTEL_SAMPLE_RESOURCE_DETECTORS: 알 수 없는 감지기 %q", name))
+		}
+	}
+	if _, err := newSampler(c); err != nil {
+		errs = append(errs, err)
+	}
+	if c.TracesProcessor != "batch" && c.TracesProcessor != "simple" {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_TRACES_PROCESSOR: \"batch\" 또는 \"simple\"이어야 합니다: %q", c.TracesProcessor))
+	}
+	if c.LogsProcessor != "batch" && c.LogsProcessor != "simple" {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_LOGS_PROCESSOR: \"batch\" 또는 \"simple\"이어야 합니다: %q", c.LogsProcessor))
+	}
+	if c.GzipLevel < 0 || c.GzipLevel > 9 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_GZIP_LEVEL: 0~9 범위여야 합니다: %d", c.GzipLevel))
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, errors.New("OTEL_SAMPLE_TLS_CERT_FILE과 OTEL_SAMPLE_TLS_KEY_FILE은 함께 설정해야 합니다"))
+	}
+	for band, ratio := range c.LogSampleRatios {
+		switch band {
+		case "trace", "debug", "info", "warn":
+		default:
+			errs = append(errs, fmt.Errorf("OTEL_SAMPLE_LOG_SAMPLING: 알 수 없는 심각도 구간 %q", band))
+		}
+		if ratio < 0 || ratio > 1 {
+			errs = append(errs, fmt.Errorf("OTEL_SAMPLE_LOG_SAMPLING: %s 비율은 0~1 범위여야 합니다: %v", band, ratio))
+		}
+	}
+	if c.RouteTimeout < 0 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_ROUTE_TIMEOUT: 음수일 수 없습니다: %v", c.RouteTimeout))
+	}
+	for route, d := range c.RouteTimeouts {
+		if d < 0 {
+			errs = append(errs, fmt.Errorf("OTEL_SAMPLE_ROUTE_TIMEOUTS: %s: 음수일 수 없습니다: %v", route, d))
+		}
+	}
+	if c.RuntimeMetrics && c.RuntimeMetricsInterval < 0 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_RUNTIME_METRICS_INTERVAL: 음수일 수 없습니다: %v", c.RuntimeMetricsInterval))
+	}
+	if c.ResourceDetectTimeout < 0 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_RESOURCE_DETECT_TIMEOUT: 음수일 수 없습니다: %v", c.ResourceDetectTimeout))
+	}
+	if c.DynamicAttributesFile != "" && c.DynamicAttributesInterval <= 0 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_DYNAMIC_ATTRIBUTES_INTERVAL: 0보다 커야 합니다: %v", c.DynamicAttributesInterval))
+	}
+	if c.AttributeValueMaxLength < 0 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_ATTRIBUTE_VALUE_MAX_LENGTH: 음수일 수 없습니다: %d", c.AttributeValueMaxLength))
+	}
+	if c.ListenAttempts < 1 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_LISTEN_ATTEMPTS: 1 이상이어야 합니다: %d", c.ListenAttempts))
+	}
+	if c.ListenRetryDelay < 0 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_LISTEN_RETRY_DELAY: 음수일 수 없습니다: %v", c.ListenRetryDelay))
+	}
+	if c.LogBatchSize < 0 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_LOG_BATCH_SIZE: 음수일 수 없습니다: %d", c.LogBatchSize))
+	}
+	if c.LogExportInterval < 0 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_LOG_EXPORT_INTERVAL: 음수일 수 없습니다: %v", c.LogExportInterval))
+	}
+	if c.StartupDelay < 0 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_STARTUP_DELAY: 음수일 수 없습니다: %v", c.StartupDelay))
+	}
+	if c.MaxSpansPerTrace < 0 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_MAX_SPANS_PER_TRACE: 음수일 수 없습니다: %d", c.MaxSpansPerTrace))
+	}
+	if c.TraceExportTimeout < 0 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_TRACE_EXPORT_TIMEOUT: 음수일 수 없습니다: %v", c.TraceExportTimeout))
+	}
+	if c.TraceBufferDir != "" && c.TraceBufferMaxBytes <= 0 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_TRACE_BUFFER_MAX_BYTES: 0보다 커야 합니다: %d", c.TraceBufferMaxBytes))
+	}
+	if c.ShutdownTimeout < 0 {
+		errs = append(errs, fmt.Errorf("-shutdown-timeout: 음수일 수 없습니다: %v", c.ShutdownTimeout))
+	}
+	if c.DemoTrafficInterval < 0 {
+		errs = append(errs, fmt.Errorf("-demo-traffic: 음수일 수 없습니다: %v", c.DemoTrafficInterval))
+	}
+	if c.ExemplarReservoirSize < 0 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_EXEMPLAR_RESERVOIR_SIZE: 음수일 수 없습니다: %d", c.ExemplarReservoirSize))
+	}
+	if c.AdminTraces && !c.Admin {
+		errs = append(errs, errors.New("OTEL_SAMPLE_ADMIN_TRACES는 OTEL_SAMPLE_ADMIN이 필요합니다"))
+	}
+	if c.ReadyRequiresOTLP && c.OTLPEndpoint == "" {
+		errs = append(errs, errors.New("OTEL_SAMPLE_READY_REQUIRES_OTLP는 OTEL_EXPORTER_OTLP_ENDPOINT가 필요합니다"))
+	}
+	for _, p := range []struct{ env, value string }{
+		{"OTEL_EXPORTER_OTLP_PROTOCOL", c.OTLPProtocol},
+		{"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", c.TracesProtocol},
+		{"OTEL_EXPORTER_OTLP_METRICS_PROTOCOL", c.MetricsProtocol},
+		{"OTEL_EXPORTER_OTLP_LOGS_PROTOCOL", c.LogsProtocol},
+	} {
+		switch p.value {
+		case "", "grpc", "http/protobuf", "http/json":
+		default:
+			errs = append(errs, fmt.Errorf("%s: 알 수 없는 프로토콜 %q", p.env, p.value))
+		}
+	}
+	if ep, err := parseOTLPEndpoint(c.OTLPEndpoint, c.OTLPInsecure); c.OTLPEndpoint != "" && err == nil && ep.path != "" {
+		for _, signal := range []string{"traces", "metrics", "logs"} {
+			if c.otlpProtocol(signal) == "grpc" {
+				errs = append(errs, fmt.Errorf("%s의 OTLP 프로토콜: 경로 접두사 %q가 있는 엔드포인트는 gRPC로 보낼 수 없습니다", signal, ep.path))
+			}
+		}
+	}
+	if c.ReadyRequiresOTLP && c.OTLPEndpoint != "" && !c.usesOTLPGRPC() {
+		errs = append(errs, errors.New("OTEL_SAMPLE_READY_REQUIRES_OTLP는 OTLP gRPC 연결이 필요합니다. 모든 신호가 OTLP/HTTP를 씁니다"))
+	}
+	if c.TracesExporter != "" {
+		errs = append(errs, c.validateExporter("OTEL_TRACES_EXPORTER", c.TracesExporter))
+	}
+	if c.MetricsExporter != "" && c.MetricsExporter != "none" {
+		errs = append(errs, c.validateExporter("OTEL_METRICS_EXPORTER", c.MetricsExporter))
+	}
+	if c.MetricsDropBuckets && c.MetricsPercentiles {
+		errs = append(errs, errors.New("OTEL_SAMPLE_METRICS_DROP_BUCKETS와 OTEL_SAMPLE_METRICS_PERCENTILES는 함께 쓸 수 없습니다. 백분위수는 버킷으로 추정합니다"))
+	}
+	if c.MetricsAddr != "" && !c.Prometheus {
+		errs = append(errs, errors.New("OTEL_SAMPLE_METRICS_ADDR는 OTEL_SAMPLE_PROMETHEUS가 필요합니다"))
+	}
+	for _, name := range c.LogsExporters {
+		errs = append(errs, c.validateExporter("OTEL_LOGS_EXPORTER", name))
+	}
+	if c.MaxHeaderBytes < 0 {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_MAX_HEADER_BYTES: 음수일 수 없습니다: %d", c.MaxHeaderBytes))
+	}
+	if (c.AuthUsername == "") != (c.AuthPassword == "") {
+		errs = append(errs, errors.New("OTEL_SAMPLE_AUTH_USERNAME과 OTEL_SAMPLE_AUTH_PASSWORD는 함께 설정해야 합니다"))
+	}
+	if len(c.FeatureFlagAttributes) > maxFeatureFlagAttributes {
+		errs = append(errs, fmt.Errorf("OTEL_SAMPLE_FEATURE_FLAG_ATTRIBUTES: 최대 %d개까지 기록할 수 있습니다: %d",
+			maxFeatureFlagAttributes, len(c.FeatureFlagAttributes)))
+	}
+	return errors.Join(errs...)
+}
+
+// validateExporter는 env로 지정된 익스포터 이름이 알려진 것인지, OTLP라면 엔드포인트가 있는지 확인합니다.
+func (c config) validateExporter(env, name string) error {
+	switch name {
+	case "stdout":
+	case "otlp":
+		if c.OTLPEndpoint == "" {
+			return fmt.Errorf("%s=otlp는 OTEL_EXPORTER_OTLP_ENDPOINT가 필요합니다", env)
+		}
+	default:
+		return fmt.Errorf("%s: 알 수 없는 익스포터 %q", env, name)
+	}
+	return nil
+}
+
+// samplerRatio는 OTEL_TRACES_SAMPLER_ARG를 비율로 읽습니다. 비어 있으면 1입니다.
+func (c config) samplerRatio() (float64, error) {
+	if c.TracesSamplerArg == "" {
+		return 1, nil
+	}
+	ratio, err := strconv.ParseFloat(c.TracesSamplerArg, 64)
+	if err != nil {
+		return 0, fmt.Errorf("OTEL_TRACES_SAMPLER_ARG: 숫자가 아닙니다: %q", c.TracesSamplerArg)
+	}
+	if ratio < 0 || ratio > 1 {
+		return 0, fmt.Errorf("OTEL_TRACES_SAMPLER_ARG: 0~1 범위여야 합니다: %v", ratio)
+	}
+	return ratio, nil
+}
+
+// validateStrict는 Strict일 때 운영에 필요한 설정이 명시되었는지 확인합니다.
+// 잘못된 서비스 이름이나 대상으로 텔레메트리를 보내는 일을 시작 전에 막습니다.
+func (c config) validateStrict() error {
+	if !c.Strict {
+		return nil
+	}
+	var errs []error
+	if !serviceNameFromEnv() {
+		errs = append(errs, errors.New("strict: OTEL_SERVICE_NAME이나 OTEL_RESOURCE_ATTRIBUTES의 service.name이 필요합니다"))
+	}
+	if c.OTLPEndpoint == "" {
+		// 명시한 otlp 익스포터는 validateExporter가 이미 거절하므로, 기본값으로 정해지는 신호만 남습니다.
+		var defaulted []string
+		if c.TracesExporter == "" {
+			defaulted = append(defaulted, "OTEL_TRACES_EXPORTER")
+		}
+		if c.MetricsExporter == "" {
+			defaulted = append(defaulted, "OTEL_METRICS_EXPORTER")
+		}
+		if len(c.LogsExporters) == 0 {
+			defaulted = append(defaulted, "OTEL_LOGS_EXPORTER")
+		}
+		if len(defaulted) > 0 {
+			errs = append(errs, fmt.Errorf("strict: OTEL_EXPORTER_OTLP_ENDPOINT가 없습니다. stdout으로 내보내려면 다음을 stdout으로 명시하세요: %s",
+				strings.Join(defaulted, ", ")))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// serviceNameFromEnv는 환경 변수로 서비스 이름이 지정되었는지 보고합니다.
+func serviceNameFromEnv() bool {
+	if strings.TrimSpace(os.Getenv("OTEL_SERVICE_NAME")) != "" {
+		return true
+	}
+	for _, kv := range strings.Split(os.Getenv("OTEL_RESOURCE_ATTRIBUTES"), ",") {
+		k, v, _ := strings.Cut(kv, "=")
+		if strings.TrimSpace(k) == "service.name" && strings.TrimSpace(v) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// otlpProtocol은 signal("traces", "metrics", "logs")을 OTLP로 보낼 전송 방식을 반환합니다.
+// 신호별 설정, 공통 설정 순으로 보고, 둘 다 없으면 엔드포인트에 경로 접두사가 있을 때
+// "http/protobuf", 없으면 "grpc"입니다.
+func (c config) otlpProtocol(signal string) string {
+	var p string
+	switch signal {
+	case "traces":
+		p = c.TracesProtocol
+	case "metrics":
+		p = c.MetricsProtocol
+	case "logs":
+		p = c.LogsProtocol
+	}
+	if p = cmp.Or(p, c.OTLPProtocol); p != "" {
+		return p
+	}
+	if ep, err := parseOTLPEndpoint(c.OTLPEndpoint, c.OTLPInsecure); err == nil && ep.path != "" {
+		return "http/protobuf"
+	}
+	return "grpc"
+}
+
+// usesOTLPGRPC는 공유 gRPC 연결을 쓰는 신호가 있는지 보고합니다.
+func (c config) usesOTLPGRPC() bool {
+	return c.otlpProtocol("traces") == "grpc" || c.otlpProtocol("metrics") == "grpc" || c.otlpProtocol("logs") == "grpc"
+}
+
+// defaultExporter는 신호별 익스포터가 지정되지 않았을 때 쓸 대상입니다.
+func (c config) defaultExporter() string {
+	if c.OTLPEndpoint != "" {
+		return "otlp"
+	}
+	return "stdout"
+}
+
+// tracesExporter는 추적을 내보낼 대상을 반환합니다.
+func (c config) tracesExporter() string {
+	return cmp.Or(c.TracesExporter, c.defaultExporter())
+}
+
+// metricsExporter는 메트릭을 내보낼 대상을 반환합니다.
+func (c config) metricsExporter() string {
+	return cmp.Or(c.MetricsExporter, c.defaultExporter())
+}
+
+// logsExporters는 로그를 내보낼 대상 목록을 반환합니다.
+func (c config) logsExporters() []string {
+	if len(c.LogsExporters) > 0 {
+		names := slices.Clone(c.LogsExporters)
+		slices.Sort(names)
+		return slices.Compact(names)
+	}
+	return []string{c.defaultExporter()}
+}
+
+// versusURL은 상대의 주사위를 받을 주소를 반환합니다. VersusURL이 없으면 ListenAddr에서
+// 자기 자신의 주소를 만들며, 모든 인터페이스에서 듣는 경우 localhost를 씁니다.
+func (c config) versusURL() string {
+	if c.VersusURL != "" {
+		return strings.TrimRight(c.VersusURL, "/")
+	}
+	scheme := "http"
+	if c.TLSCertFile != "" && c.TLSKeyFile != "" {
+		scheme = "https"
+	}
+	host, port, err := net.SplitHostPort(c.ListenAddr)
+	if err != nil {
+		return scheme + "://" + c.ListenAddr
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	return scheme + "://" + net.JoinHostPort(host, port)
+}
+
+// authEnabled는 인증 정보가 하나라도 설정되었는지 보고합니다.
+func (c config) authEnabled() bool {
+	return c.AuthUsername != "" || c.AuthToken != ""
+}
+
+// routeTimeout은 경로 패턴에 적용할 처리 제한 시간을 반환합니다.
+func (c config) routeTimeout(pattern string) time.Duration {
+	if d, ok := c.RouteTimeouts[pattern]; ok {
+		return d
+	}
+	return c.RouteTimeout
+}
+
+// traceBatchTimeout은 스팬 배치 프로세서가 모은 스팬을 내보내는 간격입니다.
+func (c config) traceBatchTimeout() time.Duration {
+	if c.Demo {
+		return time.Second
+	}
+	return 5 * time.Second
+}
+
+// metricInterval은 주기적 메트릭 리더의 내보내기 간격입니다.
+func (c config) metricInterval() time.Duration {
+	if c.Demo {
+		return 3 * time.Second
+	}
+	return time.Minute
+}
+
+// routeContentTypes는 pattern 경로가 받을 요청 본문 형식을 반환합니다. 비어 있으면 검사하지 않습니다.
+func (c config) routeContentTypes(pattern string) []string {
+	if v, ok := c.RouteContentTypes[pattern]; ok {
+		var types []string
+		for _, t := range strings.Split(v, "|") {
+			if t = strings.TrimSpace(t); t != "" {
+				types = append(types, strings.ToLower(t))
+			}
+		}
+		return types
+	}
+	return defaultRouteContentTypes[pattern]
+}
+
+// envLoader는 설정된 환경 변수만 대상 필드에 덮어쓰고
+// 파싱 에러를 모아 한 번에 보고합니다.
+//
+// file은 설정 파일에서 읽은 값으로, 같은 이름의 환경 변수가 없을 때만 씁니다.
+// 읽은 키는 seen에 남겨 err에서 파일에만 있는 알 수 없는 키를 보고합니다.
+type envLoader struct {
+	errs []error
+
+	file map[string]string
+	seen map[string]bool
+}
+
+// lookup은 환경 변수를, 없으면 설정 파일의 값을 반환합니다.
+func (l *envLoader) lookup(key string) (string, bool) {
+	if l.seen == nil {
+		l.seen = map[string]bool{}
+	}
+	l.seen[key] = true
+	if v, ok := os.LookupEnv(key); ok {
+		return v, true
+	}
+	v, ok := l.file[key]
+	return v, ok
+}
+
+func (l *envLoader) string(key string, dst *string) {
+	if v, ok := l.lookup(key); ok {
+		*dst = v
+	}
+}
+
+func (l *envLoader) bool(key string, dst *bool) {
+	if v, ok := l.lookup(key); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s: %w", key, err))
+			return
+		}
+		*dst = b
+	}
+}
+
+func (l *envLoader) int(key string, dst *int) {
+	if v, ok := l.lookup(key); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s: %w", key, err))
+			return
+		}
+		*dst = n
+	}
+}
+
+func (l *envLoader) int64(key string, dst *int64) {
+	if v, ok := l.lookup(key); ok {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s: %w", key, err))
+			return
+		}
+		*dst = n
+	}
+}
+
+func (l *envLoader) duration(key string, dst *time.Duration) {
+	if v, ok := l.lookup(key); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s: %w", key, err))
+			return
+		}
+		*dst = d
+	}
+}
+
+// stringList는 쉼표로 구분한 값을 읽습니다. 빈 항목은 건너뜁니다.
+func (l *envLoader) stringList(key string, dst *[]string) {
+	v, ok := l.lookup(key)
+	if !ok {
+		return
+	}
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	*dst = list
+}
+
+// stringMap은 "k1=v1,k2=v2" 형식의 값을 읽습니다.
+func (l *envLoader) stringMap(key string, dst *map[string]string) {
+	v, ok := l.lookup(key)
+	if !ok {
+		return
+	}
+	m := map[string]string{}
+	for _, pair := range strings.Split(v, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		k, val, found := strings.Cut(pair, "=")
+		if !found {
+			l.errs = append(l.errs, fmt.Errorf("%s: key=value 형식이 아닙니다: %q", key, pair))
+			return
+		}
+		m[strings.TrimSpace(k)] = strings.TrimSpace(val)
+	}
+	*dst = m
+}
+
+func (l *envLoader) floatMap(key string, dst *map[string]float64) {
+	var raw map[string]string
+	l.stringMap(key, &raw)
+	if raw == nil {
+		return
+	}
+	m := make(map[string]float64, len(raw))
+	for k, v := range raw {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s: %s: %w", key, k, err))
+			return
+		}
+		m[k] = f
+	}
+	*dst = m
+}
+
+func (l *envLoader) durationMap(key string, dst *map[string]time.Duration) {
+	var raw map[string]string
+	l.stringMap(key, &raw)
+	if raw == nil {
+		return
+	}
+	m := make(map[string]time.Duration, len(raw))
+	for k, v := range raw {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s: %s: %w", key, k, err))
+			return
+		}
+		m[k] = d
+	}
+	*dst = m
+}
+
+func (l *envLoader) err() error {
+	// 오타 난 키가 조용히 무시되지 않도록 알 수 없는 키는 에러로 알립니다.
+	var unknown []string
+	for key := range l.file {
+		if !l.seen[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	slices.Sort(unknown)
+	for _, key := range unknown {
+		l.errs = append(l.errs, fmt.Errorf("설정 파일: 알 수 없는 키 %q", key))
+	}
+	return errors.Join(l.errs...)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// readConfigFile은 환경 변수 이름을 키로 하는 JSON 설정 파일을 읽습니다.
+// 다시 빌드하지 않고 여러 설정을 한 파일로 배포할 때 쓰며, 같은 이름의 환경 변수가
+// 있으면 환경 변수가 이깁니다. 값은 환경 변수와 같은 형식으로 해석되므로, 예를 들어
+// 목록은 쉼표로 구분한 문자열로 적습니다. 숫자와 불리언은 따옴표 없이 써도 됩니다.
+//
+//	{
+//	  "OTEL_SAMPLE_LISTEN_ADDR": ":9000",
+//	  "OTEL_TRACES_SAMPLER": "parentbased_traceidratio",
+//	  "OTEL_TRACES_SAMPLER_ARG": 0.1,
+//	  "OTEL_SAMPLE_ADMIN": true,
+//	  "OTEL_LOGS_EXPORTER": "otlp,stdout"
+//	}
+func readConfigFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	// 0.1 같은 숫자를 float64로 바꿨다가 다시 쓰지 않고 적힌 그대로 넘깁니다.
+	dec.UseNumber()
+	var raw map[string]any
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, v := range raw {
+		switch v := v.(type) {
+		case string:
+			values[key] = v
+		case json.Number:
+			values[key] = v.String()
+		case bool:
+			values[key] = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Errorf("%s: %s: 값은 문자열, 숫자, 불리언이어야 합니다", path, key)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+var connRequestsHist metric.Int64Histogram
+
+func init() {
+	var err error
+	connRequestsHist, err = meter.Int64Histogram("http.server.connection.requests",
+		metric.WithDescription("닫힌 연결 하나가 처리한 요청 수"),
+		metric.WithUnit("{request}"),
+		metric.WithExplicitBucketBoundaries(1, 2, 5, 10, 20, 50, 100, 500, 1000))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// connReuseRecorder는 http.Server.ConnState에 연결해 연결마다 처리한 요청 수를 세고,
+// 연결이 닫힐 때 히스토그램에 기록합니다. 값이 대부분 1이면 클라이언트가 keep-alive를
+// 쓰지 않고 요청마다 새로 연결하고 있다는 뜻입니다.
+//
+// HTTP/1.x는 요청마다 StateActive로 바뀌므로 요청 수를 셀 수 있지만, HTTP/2 연결은
+// 한 번만 StateActive가 되므로 1로 기록됩니다.
+type connReuseRecorder struct {
+	mu       sync.Mutex
+	requests map[net.Conn]int64
+}
+
+func newConnReuseRecorder() *connReuseRecorder {
+	return &connReuseRecorder{requests: make(map[net.Conn]int64)}
+}
+
+func (r *connReuseRecorder) connState(c net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateActive:
+		r.mu.Lock()
+		r.requests[c]++
+		r.mu.Unlock()
+	case http.StateClosed, http.StateHijacked:
+		r.mu.Lock()
+		n, ok := r.requests[c]
+		delete(r.requests, c)
+		r.mu.Unlock()
+		// 요청 없이 닫힌 연결(예: 헬스 체크 TCP 연결)은 재사용 비율을 흐리므로 기록하지 않습니다.
+		if ok {
+			connRequestsHist.Record(context.Background(), n)
+		}
+	}
+}
+
+// chainConnState는 여러 ConnState 훅을 순서대로 호출하는 훅을 만듭니다.
+func chainConnState(hooks ...func(net.Conn, http.ConnState)) func(net.Conn, http.ConnState) {
+	return func(c net.Conn, state http.ConnState) {
+		for _, hook := range hooks {
+			hook(c, state)
+		}
+	}
+}
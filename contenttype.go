@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultRouteContentTypes는 쓰기 경로마다 받는 요청 본문 형식입니다.
+// OTEL_SAMPLE_ROUTE_CONTENT_TYPES로 경로별로 덮어쓸 수 있습니다.
+var defaultRouteContentTypes = map[string][]string{
+	"POST /admin/loglevel": {"application/x-www-form-urlencoded"},
+}
+
+// requireContentType은 요청 본문의 Content-Type을 서버 스팬의
+// http.request.header.content-type 속성으로 남기고, types에 없는 형식이면 415로 거절합니다.
+// 본문이 없는 요청(예: 쿼리 문자열만 쓰는 POST)은 검사하지 않습니다.
+// otelhttp 핸들러 안쪽에 있어야 합니다.
+func requireContentType(types []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength == 0 && r.Header.Get("Content-Type") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ct := r.Header.Get("Content-Type")
+		trace.SpanFromContext(r.Context()).SetAttributes(
+			attribute.StringSlice("http.request.header.content-type", []string{ct}))
+
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || !slices.Contains(types, mediaType) {
+			w.Header().Set("Accept-Post", strings.Join(types, ", "))
+			writeProblem(w, r, http.StatusUnsupportedMediaType,
+				fmt.Sprintf("지원하지 않는 Content-Type %q입니다. 가능한 형식: %s", ct, strings.Join(types, ", ")))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
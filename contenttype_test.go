@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestRequireContentType(t *testing.T) {
+	defer logLevel.Set(logLevel.Level())
+	level := logLevel.Level().String()
+
+	tests := []struct {
+		name        string
+		override    string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{name: "form", contentType: "application/x-www-form-urlencoded", body: "level=" + level, wantStatus: http.StatusOK},
+		{name: "form with charset", contentType: "Application/X-WWW-Form-Urlencoded; charset=utf-8", body: "level=" + level, wantStatus: http.StatusOK},
+		{name: "json rejected", contentType: "application/json", body: "{}", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "malformed", contentType: "text/", body: "level=" + level, wantStatus: http.StatusUnsupportedMediaType},
+		// 쿼리 문자열만 쓰는 POST는 본문이 없으므로 검사하지 않습니다.
+		{name: "no body", wantStatus: http.StatusOK},
+		{name: "json allowed by override", override: "application/json | application/x-www-form-urlencoded", contentType: "application/json", body: "{}", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetTelemetry(t)
+			cfg := defaultConfig()
+			cfg.Admin = true
+			if tt.override != "" {
+				cfg.RouteContentTypes = map[string]string{"POST /admin/loglevel": tt.override}
+			}
+			r := httptest.NewRequest(http.MethodPost, "/admin/loglevel?level="+level, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			w := serve(newTestHandler(cfg), r)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("상태 = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantStatus == http.StatusUnsupportedMediaType {
+				want := "application/x-www-form-urlencoded"
+				if got := w.Header().Get("Accept-Post"); got != want {
+					t.Errorf("Accept-Post = %q, want %q", got, want)
+				}
+			}
+
+			server := findSpan(t, "POST /admin/loglevel")
+			if tt.contentType == "" {
+				if _, ok := spanAttr(server, "http.request.header.content-type"); ok {
+					t.Error("본문이 없는데 content-type 속성이 있습니다")
+				}
+				return
+			}
+			assertSpanAttr(t, server, "http.request.header.content-type", attribute.StringSliceValue([]string{tt.contentType}))
+		})
+	}
+}
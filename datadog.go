@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Datadog 추적기가 쓰는 전파 헤더입니다.
+const (
+	ddTraceIDHeader  = "x-datadog-trace-id"
+	ddParentIDHeader = "x-datadog-parent-id"
+	ddPriorityHeader = "x-datadog-sampling-priority"
+	ddTagsHeader     = "x-datadog-tags"
+
+	// ddTraceIDHighTag는 128비트 트레이스 ID의 상위 64비트를 16진수로 담는 태그입니다.
+	ddTraceIDHighTag = "_dd.p.tid"
+)
+
+// datadogPropagator는 Datadog으로 계측된 서비스와 추적을 잇기 위한 전파기입니다.
+// Datadog에서 OpenTelemetry로 옮기는 동안 두 시스템 사이의 표본 추출 결정을 이어 줍니다.
+//
+// x-datadog-sampling-priority는 다음과 같이 원격 부모의 sampled 플래그가 됩니다.
+//
+//	-1 (USER_REJECT), 0 (AUTO_REJECT) → 표본 추출 안 함
+//	 1 (AUTO_KEEP),   2 (USER_KEEP)   → 표본 추출
+//
+// 우선순위 헤더가 없거나 숫자가 아니면 상류가 결정하지 않은 것이므로 Datadog 헤더를
+// 무시하고, 이 서비스의 표본 추출기가 새 루트로 결정합니다. 결정은 부모 기반
+// 표본 추출기(기본값인 parentbased_*)일 때만 지켜집니다.
+//
+// 트레이스 ID는 x-datadog-trace-id(10진수 하위 64비트)와 x-datadog-tags의 _dd.p.tid
+// (16진수 상위 64비트)로 만듭니다. 내보낼 때는 같은 형식으로 쓰고, 우선순위는 sampled
+// 플래그에 따라 1 또는 0으로 씁니다.
+type datadogPropagator struct{}
+
+var _ propagation.TextMapPropagator = datadogPropagator{}
+
+func (datadogPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := oteltrace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	tid, sid := sc.TraceID(), sc.SpanID()
+	carrier.Set(ddTraceIDHeader, strconv.FormatUint(binary.BigEndian.Uint64(tid[8:]), 10))
+	carrier.Set(ddParentIDHeader, strconv.FormatUint(binary.BigEndian.Uint64(sid[:]), 10))
+	if high := binary.BigEndian.Uint64(tid[:8]); high != 0 {
+		carrier.Set(ddTagsHeader, fmt.Sprintf("%s=%016x", ddTraceIDHighTag, high))
+	}
+	priority := "0"
+	if sc.IsSampled() {
+		priority = "1"
+	}
+	carrier.Set(ddPriorityHeader, priority)
+}
+
+func (datadogPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	priority, err := strconv.Atoi(carrier.Get(ddPriorityHeader))
+	if err != nil {
+		return ctx
+	}
+	low, err := strconv.ParseUint(carrier.Get(ddTraceIDHeader), 10, 64)
+	if err != nil {
+		return ctx
+	}
+	parent, err := strconv.ParseUint(carrier.Get(ddParentIDHeader), 10, 64)
+	if err != nil {
+		return ctx
+	}
+
+	var (
+		tid oteltrace.TraceID
+		sid oteltrace.SpanID
+	)
+	binary.BigEndian.PutUint64(tid[:8], ddTraceIDHigh(carrier.Get(ddTagsHeader)))
+	binary.BigEndian.PutUint64(tid[8:], low)
+	binary.BigEndian.PutUint64(sid[:], parent)
+
+	var flags oteltrace.TraceFlags
+	if ddPrioritySampled(priority) {
+		flags = oteltrace.FlagsSampled
+	}
+	sc := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: flags,
+		Remote:     true,
+	})
+	if !sc.IsValid() {
+		return ctx
+	}
+	return oteltrace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+func (datadogPropagator) Fields() []string {
+	return []string{ddTraceIDHeader, ddParentIDHeader, ddPriorityHeader, ddTagsHeader}
+}
+
+// ddPrioritySampled는 Datadog 표본 추출 우선순위가 보존 결정인지 보고합니다.
+func ddPrioritySampled(priority int) bool {
+	return priority > 0
+}
+
+// ddTraceIDHigh는 x-datadog-tags에서 트레이스 ID 상위 64비트를 읽습니다. 없으면 0입니다.
+func ddTraceIDHigh(tags string) uint64 {
+	for _, tag := range strings.Split(tags, ",") {
+		k, v, ok := strings.Cut(tag, "=")
+		if !ok || strings.TrimSpace(k) != ddTraceIDHighTag {
+			continue
+		}
+		if high, err := strconv.ParseUint(strings.TrimSpace(v), 16, 64); err == nil {
+			return high
+		}
+	}
+	return 0
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestDatadogPriority(t *testing.T) {
+	const (
+		low    = "1234567890123456789" // 0x112210f47de98115
+		parent = "987654321"
+		// 하위 64비트는 low, 상위 64비트는 _dd.p.tid입니다.
+		wantTraceID = "64f5a1b2c3d4e5f6112210f47de98115"
+	)
+	tests := []struct {
+		name        string
+		priority    string
+		wantSampled bool
+		wantRemote  bool
+	}{
+		{"user reject", "-1", false, true},
+		{"auto reject", "0", false, true},
+		{"auto keep", "1", true, true},
+		{"user keep", "2", true, true},
+		// 상류가 결정하지 않았으면 Datadog 헤더를 무시하고 새 루트로 결정합니다.
+		{"no priority", "", true, false},
+		{"bad priority", "keep", true, false},
+	}
+	cfg := defaultConfig()
+	cfg.DatadogPropagation = true
+	prop := newPropagator(cfg)
+	sampler, err := newSampler(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp, _ := newRecordingTracerProvider(t, nil, sdktrace.WithSampler(sampler))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			h.Set(ddTraceIDHeader, low)
+			h.Set(ddParentIDHeader, parent)
+			h.Set(ddTagsHeader, "_dd.p.dm=-1,_dd.p.tid=64f5a1b2c3d4e5f6")
+			if tt.priority != "" {
+				h.Set(ddPriorityHeader, tt.priority)
+			}
+			ctx := prop.Extract(context.Background(), propagation.HeaderCarrier(h))
+			_, span := tp.Tracer("test").Start(ctx, "server")
+			defer span.End()
+
+			sc := span.SpanContext()
+			if sc.IsSampled() != tt.wantSampled {
+				t.Errorf("sampled = %v, want %v", sc.IsSampled(), tt.wantSampled)
+			}
+			if got := sc.TraceID().String() == wantTraceID; got != tt.wantRemote {
+				t.Errorf("트레이스 ID %s, Datadog 트레이스를 이음 = %v, want %v", sc.TraceID(), got, tt.wantRemote)
+			}
+		})
+	}
+}
+
+func TestDatadogInjectRoundTrip(t *testing.T) {
+	tests := []struct {
+		name         string
+		sampled      bool
+		wantPriority string
+	}{
+		{"sampled", true, "1"},
+		{"not sampled", false, "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sampler := sdktrace.NeverSample()
+			if tt.sampled {
+				sampler = sdktrace.AlwaysSample()
+			}
+			tp, _ := newRecordingTracerProvider(t, nil, sdktrace.WithSampler(sampler))
+			ctx, span := tp.Tracer("test").Start(context.Background(), "client")
+			defer span.End()
+
+			h := http.Header{}
+			datadogPropagator{}.Inject(ctx, propagation.HeaderCarrier(h))
+			if got := h.Get(ddPriorityHeader); got != tt.wantPriority {
+				t.Errorf("%s = %q, want %q", ddPriorityHeader, got, tt.wantPriority)
+			}
+			got := oteltrace.SpanContextFromContext(datadogPropagator{}.Extract(context.Background(), propagation.HeaderCarrier(h)))
+			if got.TraceID() != span.SpanContext().TraceID() || got.SpanID() != span.SpanContext().SpanID() {
+				t.Errorf("되읽은 컨텍스트 %s/%s, want %s/%s", got.TraceID(), got.SpanID(),
+					span.SpanContext().TraceID(), span.SpanContext().SpanID())
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// W3C trace-flags에는 표본 추출(sampled) 비트만 정의되어 있고 디버그 비트가 없습니다.
+// 정의되지 않은 비트는 TraceContext 전파기가 버리므로, 디버그 표시는 tracestate의
+// debugTraceStateKey 항목으로 전파합니다. tracestate는 traceparent와 함께 전파기가
+// 그대로 넘기므로 이 서비스를 거치는 모든 하위 요청에 이어집니다.
+const debugTraceStateKey = "debug"
+
+type debugTraceKey struct{}
+
+// withDebugTrace는 ctx에서 시작하는 스팬과 그 아래의 모든 스팬, 나가는 요청을 디버그
+// 추적으로 표시합니다. 표본 추출 비율과 관계없이 기록되어 특정 요청만 골라 추적할 수 있습니다.
+func withDebugTrace(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugTraceKey{}, true)
+}
+
+// debugSampler는 디버그로 표시된 스팬을 항상 표본 추출하고, 나머지는 next에 맡깁니다.
+// 디버그 스팬은 tracestate에 debug=1을 넣어 자식과 하위 서비스로 전파하고
+// trace.debug=true 속성을 남깁니다.
+//
+// 신뢰: 들어오는 요청의 debug 항목은 누구나 넣을 수 있고, 따르면 요청마다 표본 추출을
+// 강제하게 되어 추적 양과 비용이 호출자 마음대로 늘어납니다. 그래서 원격 부모의 debug
+// 항목은 trustRemote일 때만 따릅니다. 신뢰할 수 있는 게이트웨이가 외부에서 온 tracestate를
+// 지워 주는 환경에서만 켜세요. 따르지 않은 항목도 tracestate에는 남아 하위 서비스로 전달되며,
+// 하위 서비스가 따를지는 각자의 설정에 달려 있습니다.
+type debugSampler struct {
+	next        trace.Sampler
+	trustRemote bool
+}
+
+func newDebugSampler(next trace.Sampler, trustRemote bool) trace.Sampler {
+	return debugSampler{next: next, trustRemote: trustRemote}
+}
+
+func (s debugSampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	psc := oteltrace.SpanContextFromContext(p.ParentContext)
+	debug, _ := p.ParentContext.Value(debugTraceKey{}).(bool)
+	// 로컬 부모가 표본 추출되지 않았다면 원격 부모의 debug 항목을 따르지 않은 것이므로
+	// tracestate만 물려받았더라도 디버그로 보지 않습니다.
+	if psc.TraceState().Get(debugTraceStateKey) == "1" {
+		if psc.IsRemote() {
+			debug = debug || s.trustRemote
+		} else {
+			debug = debug || psc.IsSampled()
+		}
+	}
+	if !debug {
+		return s.next.ShouldSample(p)
+	}
+	ts, err := psc.TraceState().Insert(debugTraceStateKey, "1")
+	if err != nil {
+		ts = psc.TraceState()
+	}
+	return trace.SamplingResult{
+		Decision:   trace.RecordAndSample,
+		Attributes: []attribute.KeyValue{attribute.Bool("trace.debug", true)},
+		Tracestate: ts,
+	}
+}
+
+func (s debugSampler) Description() string {
+	return "DebugSampler{" + s.next.Description() + "}"
+}
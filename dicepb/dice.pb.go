@@ -0,0 +1,252 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        (unknown)
+// source: dicepb/dice.proto
+
+package dicepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RollDiceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// player가 비어 있으면 익명의 플레이어로 던집니다.
+	Player string `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
+}
+
+func (x *RollDiceRequest) Reset() {
+	*x = RollDiceRequest{}
+	mi := &file_dicepb_dice_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollDiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollDiceRequest) ProtoMessage() {}
+
+func (x *RollDiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dicepb_dice_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollDiceRequest.ProtoReflect.Descriptor instead.
+func (*RollDiceRequest) Descriptor() ([]byte, []int) {
+	return file_dicepb_dice_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RollDiceRequest) GetPlayer() string {
+	if x != nil {
+		return x.Player
+	}
+	return ""
+}
+
+type RollDiceStreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Player string `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
+	// count는 던질 횟수입니다. 1 이상이어야 합니다.
+	Count int32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *RollDiceStreamRequest) Reset() {
+	*x = RollDiceStreamRequest{}
+	mi := &file_dicepb_dice_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollDiceStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollDiceStreamRequest) ProtoMessage() {}
+
+func (x *RollDiceStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dicepb_dice_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollDiceStreamRequest.ProtoReflect.Descriptor instead.
+func (*RollDiceStreamRequest) Descriptor() ([]byte, []int) {
+	return file_dicepb_dice_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RollDiceStreamRequest) GetPlayer() string {
+	if x != nil {
+		return x.Player
+	}
+	return ""
+}
+
+func (x *RollDiceStreamRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type RollDiceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// value는 1~6 사이의 주사위 값입니다.
+	Value int32 `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *RollDiceResponse) Reset() {
+	*x = RollDiceResponse{}
+	mi := &file_dicepb_dice_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollDiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollDiceResponse) ProtoMessage() {}
+
+func (x *RollDiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dicepb_dice_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollDiceResponse.ProtoReflect.Descriptor instead.
+func (*RollDiceResponse) Descriptor() ([]byte, []int) {
+	return file_dicepb_dice_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RollDiceResponse) GetValue() int32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+var File_dicepb_dice_proto protoreflect.FileDescriptor
+
+var file_dicepb_dice_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x64, 0x69, 0x63, 0x65, 0x70, 0x62, 0x2f, 0x64, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x07, 0x64, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x22, 0x29, 0x0a, 0x0f,
+	0x52, 0x6f, 0x6c, 0x6c, 0x44, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x22, 0x45, 0x0a, 0x15, 0x52, 0x6f, 0x6c, 0x6c, 0x44,
+	0x69, 0x63, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x28,
+	0x0a, 0x10, 0x52, 0x6f, 0x6c, 0x6c, 0x44, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x32, 0x9d, 0x01, 0x0a, 0x0b, 0x44, 0x69, 0x63,
+	0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3f, 0x0a, 0x08, 0x52, 0x6f, 0x6c, 0x6c,
+	0x44, 0x69, 0x63, 0x65, 0x12, 0x18, 0x2e, 0x64, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x6f, 0x6c, 0x6c, 0x44, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
+	0x2e, 0x64, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6c, 0x6c, 0x44, 0x69, 0x63,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x0e, 0x52, 0x6f, 0x6c,
+	0x6c, 0x44, 0x69, 0x63, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1e, 0x2e, 0x64, 0x69,
+	0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6c, 0x6c, 0x44, 0x69, 0x63, 0x65, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x64, 0x69,
+	0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6c, 0x6c, 0x44, 0x69, 0x63, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x20, 0x5a, 0x1e, 0x67, 0x6f, 0x2d, 0x6f,
+	0x70, 0x65, 0x6e, 0x74, 0x65, 0x6c, 0x65, 0x6d, 0x65, 0x74, 0x72, 0x79, 0x2d, 0x73, 0x61, 0x6d,
+	0x70, 0x6c, 0x65, 0x2f, 0x64, 0x69, 0x63, 0x65, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_dicepb_dice_proto_rawDescOnce sync.Once
+	file_dicepb_dice_proto_rawDescData = file_dicepb_dice_proto_rawDesc
+)
+
+func file_dicepb_dice_proto_rawDescGZIP() []byte {
+	file_dicepb_dice_proto_rawDescOnce.Do(func() {
+		file_dicepb_dice_proto_rawDescData = protoimpl.X.CompressGZIP(file_dicepb_dice_proto_rawDescData)
+	})
+	return file_dicepb_dice_proto_rawDescData
+}
+
+var file_dicepb_dice_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_dicepb_dice_proto_goTypes = []any{
+	(*RollDiceRequest)(nil),       // 0: dice.v1.RollDiceRequest
+	(*RollDiceStreamRequest)(nil), // 1: dice.v1.RollDiceStreamRequest
+	(*RollDiceResponse)(nil),      // 2: dice.v1.RollDiceResponse
+}
+var file_dicepb_dice_proto_depIdxs = []int32{
+	0, // 0: dice.v1.DiceService.RollDice:input_type -> dice.v1.RollDiceRequest
+	1, // 1: dice.v1.DiceService.RollDiceStream:input_type -> dice.v1.RollDiceStreamRequest
+	2, // 2: dice.v1.DiceService.RollDice:output_type -> dice.v1.RollDiceResponse
+	2, // 3: dice.v1.DiceService.RollDiceStream:output_type -> dice.v1.RollDiceResponse
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_dicepb_dice_proto_init() }
+func file_dicepb_dice_proto_init() {
+	if File_dicepb_dice_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_dicepb_dice_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_dicepb_dice_proto_goTypes,
+		DependencyIndexes: file_dicepb_dice_proto_depIdxs,
+		MessageInfos:      file_dicepb_dice_proto_msgTypes,
+	}.Build()
+	File_dicepb_dice_proto = out.File
+	file_dicepb_dice_proto_rawDesc = nil
+	file_dicepb_dice_proto_goTypes = nil
+	file_dicepb_dice_proto_depIdxs = nil
+}
@@ -0,0 +1,171 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: dicepb/dice.proto
+
+package dicepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DiceService_RollDice_FullMethodName       = "/dice.v1.DiceService/RollDice"
+	DiceService_RollDiceStream_FullMethodName = "/dice.v1.DiceService/RollDiceStream"
+)
+
+// DiceServiceClient is the client API for DiceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DiceService는 HTTP의 /rolldice/{player}와 같은 주사위 던지기를 gRPC로 제공합니다.
+type DiceServiceClient interface {
+	// RollDice는 주사위를 한 번 던집니다.
+	RollDice(ctx context.Context, in *RollDiceRequest, opts ...grpc.CallOption) (*RollDiceResponse, error)
+	// RollDiceStream은 주사위를 count번 던져 값을 하나씩 보냅니다.
+	RollDiceStream(ctx context.Context, in *RollDiceStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RollDiceResponse], error)
+}
+
+type diceServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDiceServiceClient(cc grpc.ClientConnInterface) DiceServiceClient {
+	return &diceServiceClient{cc}
+}
+
+func (c *diceServiceClient) RollDice(ctx context.Context, in *RollDiceRequest, opts ...grpc.CallOption) (*RollDiceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RollDiceResponse)
+	err := c.cc.Invoke(ctx, DiceService_RollDice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *diceServiceClient) RollDiceStream(ctx context.Context, in *RollDiceStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RollDiceResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DiceService_ServiceDesc.Streams[0], DiceService_RollDiceStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RollDiceStreamRequest, RollDiceResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DiceService_RollDiceStreamClient = grpc.ServerStreamingClient[RollDiceResponse]
+
+// DiceServiceServer is the server API for DiceService service.
+// All implementations must embed UnimplementedDiceServiceServer
+// for forward compatibility.
+//
+// DiceService는 HTTP의 /rolldice/{player}와 같은 주사위 던지기를 gRPC로 제공합니다.
+type DiceServiceServer interface {
+	// RollDice는 주사위를 한 번 던집니다.
+	RollDice(context.Context, *RollDiceRequest) (*RollDiceResponse, error)
+	// RollDiceStream은 주사위를 count번 던져 값을 하나씩 보냅니다.
+	RollDiceStream(*RollDiceStreamRequest, grpc.ServerStreamingServer[RollDiceResponse]) error
+	mustEmbedUnimplementedDiceServiceServer()
+}
+
+// UnimplementedDiceServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDiceServiceServer struct{}
+
+func (UnimplementedDiceServiceServer) RollDice(context.Context, *RollDiceRequest) (*RollDiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollDice not implemented")
+}
+func (UnimplementedDiceServiceServer) RollDiceStream(*RollDiceStreamRequest, grpc.ServerStreamingServer[RollDiceResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method RollDiceStream not implemented")
+}
+func (UnimplementedDiceServiceServer) mustEmbedUnimplementedDiceServiceServer() {}
+func (UnimplementedDiceServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeDiceServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DiceServiceServer will
+// result in compilation errors.
+type UnsafeDiceServiceServer interface {
+	mustEmbedUnimplementedDiceServiceServer()
+}
+
+func RegisterDiceServiceServer(s grpc.ServiceRegistrar, srv DiceServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDiceServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DiceService_ServiceDesc, srv)
+}
+
+func _DiceService_RollDice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollDiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiceServiceServer).RollDice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DiceService_RollDice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiceServiceServer).RollDice(ctx, req.(*RollDiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DiceService_RollDiceStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RollDiceStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DiceServiceServer).RollDiceStream(m, &grpc.GenericServerStream[RollDiceStreamRequest, RollDiceResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DiceService_RollDiceStreamServer = grpc.ServerStreamingServer[RollDiceResponse]
+
+// DiceService_ServiceDesc is the grpc.ServiceDesc for DiceService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DiceService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dice.v1.DiceService",
+	HandlerType: (*DiceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RollDice",
+			Handler:    _DiceService_RollDice_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RollDiceStream",
+			Handler:       _DiceService_RollDiceStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "dicepb/dice.proto",
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+// diskBufferExporter는 내보내기에 실패한 스팬 배치를 dir 아래에 OTLP JSON 파일로 저장하고,
+// 다음 내보내기가 성공하면(수집기가 다시 연결되었다고 보고) 저장된 배치를 오래된 순서로 다시
+// 내보냅니다. 프로세스가 다시 시작되어도 남은 파일은 첫 성공 뒤에 재전송됩니다.
+//
+// 데이터 손실:
+//   - 저장된 배치의 총 크기가 maxBytes를 넘으면 가장 오래된 배치부터 지웁니다.
+//     maxBytes보다 큰 배치 하나는 저장하지 않고 버리며, 이때는 내보내기 에러를 그대로 반환합니다.
+//     버릴 때마다 로그를 남깁니다.
+//   - 재전송은 최소 한 번(at-least-once)입니다. 재전송이 성공한 뒤 파일을 지우기 전에
+//     죽으면 같은 배치가 다시 전송될 수 있습니다.
+//   - 파일은 임시 파일에 쓴 뒤 이름을 바꾸므로, 쓰는 도중 죽어도 반쯤 쓴 배치는 남지 않습니다.
+type diskBufferExporter struct {
+	next     trace.SpanExporter
+	dir      string
+	maxBytes int64
+
+	mu sync.Mutex
+}
+
+// newDiskBufferExporter는 dir을 만들고 next를 감쌉니다.
+func newDiskBufferExporter(next trace.SpanExporter, dir string, maxBytes int64) (*diskBufferExporter, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &diskBufferExporter{next: next, dir: dir, maxBytes: maxBytes}, nil
+}
+
+func (e *diskBufferExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := e.next.ExportSpans(ctx, spans); err != nil {
+		stored, perr := e.persist(spans)
+		if perr != nil {
+			return errors.Join(err, fmt.Errorf("실패한 스팬 배치 저장: %w", perr))
+		}
+		if !stored {
+			// 버린 배치는 다시 내보낼 수 없으므로 원래 에러를 그대로 돌려줍니다.
+			return err
+		}
+		log.Printf("스팬 %d개를 내보내지 못해 %s에 저장했습니다: %v", len(spans), e.dir, err)
+		return nil
+	}
+	return e.replay(ctx)
+}
+
+// persist는 배치를 새 파일로 저장하고, 상한을 넘으면 오래된 배치를 지웁니다.
+// 배치가 상한보다 커서 저장하지 않고 버렸으면 false를 반환합니다.
+func (e *diskBufferExporter) persist(spans []trace.ReadOnlySpan) (bool, error) {
+	b, err := marshalOTLPJSON(&coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: spansToProto(spans),
+	})
+	if err != nil {
+		return false, err
+	}
+	if int64(len(b)) > e.maxBytes {
+		log.Printf("스팬 배치(%d바이트)가 디스크 버퍼 상한 %d바이트보다 커서 버립니다", len(b), e.maxBytes)
+		return false, nil
+	}
+
+	files, used, err := e.pending()
+	if err != nil {
+		return false, err
+	}
+	for len(files) > 0 && used+int64(len(b)) > e.maxBytes {
+		if err := os.Remove(files[0].path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return false, err
+		}
+		log.Printf("디스크 버퍼가 가득 차 가장 오래된 배치 %s를 버립니다", filepath.Base(files[0].path))
+		used -= files[0].size
+		files = files[1:]
+	}
+
+	// 파일 이름순이 저장 순서가 되도록 고정 폭 타임스탬프를 씁니다.
+	name := filepath.Join(e.dir, fmt.Sprintf("%020d.json", time.Now().UnixNano()))
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// replay는 저장된 배치를 오래된 순서로 다시 내보냅니다. 하나라도 실패하면 멈추고
+// 남은 배치는 다음 성공 때 다시 시도합니다.
+func (e *diskBufferExporter) replay(ctx context.Context) error {
+	files, _, err := e.pending()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		spans, err := readBufferedBatch(f.path)
+		if err != nil {
+			// 읽을 수 없는 파일은 다시 시도해도 소용없으므로 지웁니다.
+			log.Printf("디스크 버퍼의 배치 %s를 읽지 못해 버립니다: %v", filepath.Base(f.path), err)
+			os.Remove(f.path)
+			continue
+		}
+		if err := e.next.ExportSpans(ctx, spans); err != nil {
+			// 이번 배치는 이미 성공했으므로 재전송 실패는 에러로 올리지 않습니다.
+			return nil
+		}
+		if err := os.Remove(f.path); err != nil {
+			return err
+		}
+		log.Printf("디스크 버퍼의 스팬 %d개를 다시 내보냈습니다", len(spans))
+	}
+	return nil
+}
+
+type bufferedBatch struct {
+	path string
+	size int64
+}
+
+// pending은 저장된 배치를 오래된 순서로, 그리고 전체 크기를 반환합니다.
+func (e *diskBufferExporter) pending() ([]bufferedBatch, int64, error) {
+	entries, err := os.ReadDir(e.dir)
+	if err != nil {
+		return nil, 0, err
+	}
+	var (
+		files []bufferedBatch
+		used  int64
+	)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, bufferedBatch{path: filepath.Join(e.dir, entry.Name()), size: info.Size()})
+		used += info.Size()
+	}
+	return files, used, nil
+}
+
+// readBufferedBatch는 저장된 배치를 원래 ID와 타임스탬프 그대로 되돌립니다.
+func readBufferedBatch(path string) ([]trace.ReadOnlySpan, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	// 나노초 타임스탬프가 float64로 바뀌며 잘리지 않도록 숫자를 그대로 둡니다.
+	dec := json.NewDecoder(f)
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	req, err := unmarshalOTLPJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	return protoToSpans(req.GetResourceSpans(), 0, nil)
+}
+
+func (e *diskBufferExporter) Shutdown(ctx context.Context) error {
+	return e.next.Shutdown(ctx)
+}
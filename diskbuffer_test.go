@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+var errCollectorDown = errors.New("collector down")
+
+// flakyExporter는 down이면 실패하고, 아니면 받은 스팬을 모으는 익스포터입니다.
+type flakyExporter struct {
+	mu    sync.Mutex
+	down  bool
+	spans []trace.ReadOnlySpan
+}
+
+func (e *flakyExporter) ExportSpans(_ context.Context, spans []trace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.down {
+		return errCollectorDown
+	}
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *flakyExporter) Shutdown(context.Context) error { return nil }
+
+func (e *flakyExporter) setDown(down bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.down = down
+}
+
+// names는 지금까지 받은 스팬 이름을 받은 순서대로 반환합니다.
+func (e *flakyExporter) names() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	names := make([]string, len(e.spans))
+	for i, s := range e.spans {
+		names[i] = s.Name()
+	}
+	return names
+}
+
+// testBatch는 이름이 spanName이고 traceID 바이트로 채운 트레이스 ID를 가진 스팬 하나짜리 배치입니다.
+func testBatch(spanName string, traceID byte) []trace.ReadOnlySpan {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	return tracetest.SpanStubs{{
+		Name: spanName,
+		SpanContext: oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+			TraceID:    oteltrace.TraceID{traceID, 1},
+			SpanID:     oteltrace.SpanID{traceID, 2},
+			TraceFlags: oteltrace.FlagsSampled,
+		}),
+		StartTime: start,
+		EndTime:   start.Add(time.Millisecond),
+		Resource:  resource.Empty(),
+	}}.Snapshots()
+}
+
+// bufferedFiles는 dir에 저장된 배치 파일 수를 반환합니다.
+func bufferedFiles(t *testing.T, dir string) int {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(files)
+}
+
+func TestDiskBufferPersistAndReplay(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	next := &flakyExporter{down: true}
+	e, err := newDiskBufferExporter(next, dir, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 수집기가 내려가 있는 동안에는 배치를 저장하고 성공으로 처리합니다.
+	for i, name := range []string{"first", "second"} {
+		if err := e.ExportSpans(ctx, testBatch(name, byte(i+1))); err != nil {
+			t.Fatalf("저장할 때 ExportSpans = %v", err)
+		}
+	}
+	if n := bufferedFiles(t, dir); n != 2 {
+		t.Fatalf("저장된 배치 %d개, want 2", n)
+	}
+
+	// 다음 성공 뒤에 저장된 배치를 오래된 순서로 다시 내보내고 지웁니다.
+	next.setDown(false)
+	if err := e.ExportSpans(ctx, testBatch("third", 3)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := next.names(), []string{"third", "first", "second"}; !slices.Equal(got, want) {
+		t.Errorf("내보낸 순서 = %q, want %q", got, want)
+	}
+	if n := bufferedFiles(t, dir); n != 0 {
+		t.Errorf("재전송 뒤 남은 배치 %d개", n)
+	}
+	// 저장했다가 되돌린 스팬도 원래 ID와 시간을 그대로 가집니다.
+	replayed, want := next.spans[1], testBatch("first", 1)[0]
+	if replayed.SpanContext().TraceID() != want.SpanContext().TraceID() || !replayed.StartTime().Equal(want.StartTime()) {
+		t.Errorf("재전송한 스팬 = %v %v, want %v %v",
+			replayed.SpanContext().TraceID(), replayed.StartTime(), want.SpanContext().TraceID(), want.StartTime())
+	}
+}
+
+func TestDiskBufferLimits(t *testing.T) {
+	ctx := context.Background()
+
+	// 배치 하나의 파일 크기를 재서 상한을 정합니다.
+	probeDir := t.TempDir()
+	probe, err := newDiskBufferExporter(&flakyExporter{down: true}, probeDir, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := probe.ExportSpans(ctx, testBatch("probe", 9)); err != nil {
+		t.Fatal(err)
+	}
+	files, _, err := probe.pending()
+	if err != nil || len(files) != 1 {
+		t.Fatalf("pending = %v, %v", files, err)
+	}
+	batchSize := files[0].size
+
+	tests := []struct {
+		name      string
+		maxBytes  int64
+		wantErr   bool
+		wantFiles int
+		wantNames []string
+	}{
+		// 두 배치가 들어가지 않으면 가장 오래된 배치를 버립니다.
+		{"evicts oldest", batchSize * 3 / 2, false, 1, []string{"b"}},
+		{"fits both", batchSize * 2, false, 2, []string{"a", "b"}},
+		// 배치 하나가 상한보다 크면 저장하지 않고 원래 에러를 돌려줍니다.
+		{"oversized batch", batchSize / 2, true, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			next := &flakyExporter{down: true}
+			e, err := newDiskBufferExporter(next, dir, tt.maxBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, name := range []string{"a", "b"} {
+				err := e.ExportSpans(ctx, testBatch(name, byte(i+1)))
+				if got := errors.Is(err, errCollectorDown); got != tt.wantErr {
+					t.Errorf("%s: ExportSpans = %v, want 에러 %v", name, err, tt.wantErr)
+				}
+			}
+			if n := bufferedFiles(t, dir); n != tt.wantFiles {
+				t.Errorf("저장된 배치 %d개, want %d", n, tt.wantFiles)
+			}
+
+			next.setDown(false)
+			if err := e.ExportSpans(ctx, testBatch("c", 3)); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := next.names(), append([]string{"c"}, tt.wantNames...); !slices.Equal(got, want) {
+				t.Errorf("내보낸 순서 = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestDiskBufferCorruptBatch(t *testing.T) {
+	// 읽을 수 없는 파일은 재전송을 막지 않고 지워집니다.
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "00000000000000000001.json"), []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	next := &flakyExporter{}
+	e, err := newDiskBufferExporter(next, dir, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.ExportSpans(context.Background(), testBatch("fresh", 1)); err != nil {
+		t.Fatal(err)
+	}
+	if got := next.names(); !slices.Equal(got, []string{"fresh"}) {
+		t.Errorf("내보낸 스팬 = %q", got)
+	}
+	if n := bufferedFiles(t, dir); n != 0 {
+		t.Errorf("손상된 배치가 남았습니다")
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	llog "log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime/pprof"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// telemetryDumper는 사후 분석을 위해 진행 중인 스팬과 현재 메트릭 값을 모아 둡니다.
+type telemetryDumper struct {
+	dir     string
+	spans   *activeSpanTracker
+	metrics *metric.ManualReader
+}
+
+func newTelemetryDumper(dir string) *telemetryDumper {
+	return &telemetryDumper{
+		dir:     dir,
+		spans:   newActiveSpanTracker(),
+		metrics: metric.NewManualReader(),
+	}
+}
+
+// watch는 SIGQUIT을 기다렸다가 덤프를 기록합니다.
+// 기록이 끝나면 시그널 처리를 원래대로 되돌리고 SIGQUIT을 다시 보내
+// Go 런타임의 기본 스택 덤프와 종료가 그대로 이어지게 합니다.
+// 반환된 함수는 대기를 중단합니다.
+func (d *telemetryDumper) watch() (stop func(context.Context) error) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGQUIT)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-sigCh:
+		case <-done:
+			return
+		}
+		if err := d.write(context.Background()); err != nil {
+			llog.Printf("텔레메트리 덤프 실패: %v", err)
+		}
+		signal.Reset(syscall.SIGQUIT)
+		if p, err := os.FindProcess(os.Getpid()); err == nil {
+			_ = p.Signal(syscall.SIGQUIT)
+		}
+	}()
+
+	var once sync.Once
+	return func(context.Context) error {
+		once.Do(func() {
+			signal.Stop(sigCh)
+			close(done)
+		})
+		return nil
+	}
+}
+
+// write는 고루틴 프로파일과 텔레메트리 스냅샷을 각각 파일로 기록합니다.
+func (d *telemetryDumper) write(ctx context.Context) error {
+	if err := os.MkdirAll(d.dir, 0o755); err != nil {
+		return err
+	}
+	stamp := time.Now().Format("20060102T150405")
+
+	goroutinePath := filepath.Join(d.dir, fmt.Sprintf("goroutines-%s.txt", stamp))
+	f, err := os.Create(goroutinePath)
+	if err != nil {
+		return err
+	}
+	err = pprof.Lookup("goroutine").WriteTo(f, 2)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	snapshot := telemetrySnapshot{
+		Time:        time.Now(),
+		ActiveSpans: d.spans.snapshot(),
+	}
+	if err := d.metrics.Collect(ctx, &snapshot.Metrics); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(snapshot, "", "\t")
+	if err != nil {
+		return err
+	}
+	snapshotPath := filepath.Join(d.dir, fmt.Sprintf("telemetry-%s.json", stamp))
+	if err := os.WriteFile(snapshotPath, b, 0o644); err != nil {
+		return err
+	}
+
+	llog.Printf("텔레메트리 덤프 기록: %s, %s", goroutinePath, snapshotPath)
+	return nil
+}
+
+type telemetrySnapshot struct {
+	Time        time.Time                  `json:"time"`
+	ActiveSpans []activeSpan               `json:"active_spans"`
+	Metrics     metricdata.ResourceMetrics `json:"metrics"`
+}
+
+type activeSpan struct {
+	Name       string               `json:"name"`
+	TraceID    string               `json:"trace_id"`
+	SpanID     string               `json:"span_id"`
+	ParentID   string               `json:"parent_span_id,omitempty"`
+	StartTime  time.Time            `json:"start_time"`
+	Attributes []attribute.KeyValue `json:"attributes,omitempty"`
+}
+
+// activeSpanTracker는 시작되었지만 아직 끝나지 않은 스팬을 추적하는 SpanProcessor입니다.
+type activeSpanTracker struct {
+	mu    sync.Mutex
+	spans map[oteltrace.SpanID]trace.ReadWriteSpan
+}
+
+func newActiveSpanTracker() *activeSpanTracker {
+	return &activeSpanTracker{spans: map[oteltrace.SpanID]trace.ReadWriteSpan{}}
+}
+
+func (t *activeSpanTracker) OnStart(_ context.Context, s trace.ReadWriteSpan) {
+	t.mu.Lock()
+	t.spans[s.SpanContext().SpanID()] = s
+	t.mu.Unlock()
+}
+
+func (t *activeSpanTracker) OnEnd(s trace.ReadOnlySpan) {
+	t.mu.Lock()
+	delete(t.spans, s.SpanContext().SpanID())
+	t.mu.Unlock()
+}
+
+func (t *activeSpanTracker) Shutdown(context.Context) error   { return nil }
+func (t *activeSpanTracker) ForceFlush(context.Context) error { return nil }
+
+func (t *activeSpanTracker) snapshot() []activeSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]activeSpan, 0, len(t.spans))
+	for _, s := range t.spans {
+		as := activeSpan{
+			Name:       s.Name(),
+			TraceID:    s.SpanContext().TraceID().String(),
+			SpanID:     s.SpanContext().SpanID().String(),
+			StartTime:  s.StartTime(),
+			Attributes: s.Attributes(),
+		}
+		if p := s.Parent(); p.SpanID().IsValid() {
+			as.ParentID = p.SpanID().String()
+		}
+		out = append(out, as)
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// dynamicAttributeProcessor는 주기적으로 다시 읽는 파일의 속성을 새로 시작하는 모든 스팬에
+// 붙입니다. 리소스는 시작할 때 한 번 정해지고 바뀌지 않으므로, 교체되는 인스턴스 토큰이나
+// 현재 리더 여부처럼 실행 중에 바뀌는 값은 리소스 대신 이렇게 스팬 속성으로 남깁니다.
+// 스팬은 시작 시점의 값을 갖고, 이미 시작한 스팬은 새로 읽은 값으로 바뀌지 않습니다.
+//
+// 파일 형식은 리소스 속성 파일과 같은 "key=value" 줄입니다. 다시 읽다가 실패하면 로그를
+// 남기고 마지막으로 읽은 값을 계속 씁니다.
+type dynamicAttributeProcessor struct {
+	path  string
+	attrs atomic.Pointer[[]attribute.KeyValue]
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// newDynamicAttributeProcessor는 path를 한 번 읽은 뒤 interval마다 다시 읽는 고루틴을 시작합니다.
+// 처음 읽기에 실패하면 에러를 반환합니다. 고루틴은 Shutdown에서 멈춥니다.
+func newDynamicAttributeProcessor(path string, interval time.Duration) (*dynamicAttributeProcessor, error) {
+	p := &dynamicAttributeProcessor{
+		path: path,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	if err := p.refresh(); err != nil {
+		return nil, err
+	}
+	go p.run(interval)
+	return p, nil
+}
+
+func (p *dynamicAttributeProcessor) run(interval time.Duration) {
+	defer close(p.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			if err := p.refresh(); err != nil {
+				log.Printf("동적 속성을 다시 읽지 못해 이전 값을 씁니다: %v", err)
+			}
+		}
+	}
+}
+
+func (p *dynamicAttributeProcessor) refresh() error {
+	attrs, err := readResourceAttributesFile(p.path)
+	if err != nil {
+		return err
+	}
+	p.attrs.Store(&attrs)
+	return nil
+}
+
+func (p *dynamicAttributeProcessor) OnStart(_ context.Context, s trace.ReadWriteSpan) {
+	if attrs := p.attrs.Load(); attrs != nil {
+		s.SetAttributes(*attrs...)
+	}
+}
+
+func (p *dynamicAttributeProcessor) OnEnd(trace.ReadOnlySpan)         {}
+func (p *dynamicAttributeProcessor) ForceFlush(context.Context) error { return nil }
+
+func (p *dynamicAttributeProcessor) Shutdown(ctx context.Context) error {
+	p.stopOnce.Do(func() { close(p.stop) })
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+)
+
+// 이 파일은 신호별 익스포터를 고르는 유일한 곳입니다. 대상 이름은
+// OTEL_TRACES_EXPORTER, OTEL_METRICS_EXPORTER, OTEL_LOGS_EXPORTER에서 오며("stdout", "otlp"),
+// 제공자 생성 함수는 여기서 만든 익스포터를 받기만 합니다.
+//
+// "otlp"는 신호마다 cfg.otlpProtocol이 고른 전송 방식으로 보냅니다. "grpc"는 setupOTelSDK가
+// 만든 공유 gRPC 연결(conn)을 쓰고, "http/protobuf"는 SDK의 OTLP/HTTP 익스포터,
+// "http/json"은 otlphttpjson.go의 익스포터로 "<경로 접두사>/v1/<신호>"에 보냅니다.
+// validate가 OTLP 엔드포인트 없이 "otlp"를 고른 설정을 거절하지만, 그래도 보낼 곳이
+// 없으면 에러를 반환합니다.
+
+var errNoOTLPConn = errors.New("otlp 익스포터는 OTEL_EXPORTER_OTLP_ENDPOINT가 필요합니다")
+
+// otlpHTTPEndpoint는 OTLP/HTTP 익스포터가 보낼 엔드포인트를 반환합니다.
+func otlpHTTPEndpoint(cfg config) (otlpEndpoint, error) {
+	if cfg.OTLPEndpoint == "" {
+		return otlpEndpoint{}, errNoOTLPConn
+	}
+	return parseOTLPEndpoint(cfg.OTLPEndpoint, cfg.OTLPInsecure)
+}
+
+// newTraceExporter는 설정된 대상에 맞는 추적 익스포터를 만듭니다.
+// stdout 익스포터는 기본적으로 JSON을 한 줄씩 출력하며, "pretty" 형식일 때만 들여씁니다.
+// "otlpjson" 형식은 OTLP JSON을 한 줄씩 출력하므로 jq 등으로 바로 처리할 수 있습니다.
+//
+// cfg.Offline이면 대상 이름과 관계없이 출력을 io.Discard로 버리는 stdout 익스포터를 씁니다.
+// 익스포터를 거치는 코드(래퍼, 프로세서)는 그대로 실행되지만 아무것도 내보내지 않습니다.
+// newMetricExporter와 newLogExporters도 같습니다.
+func newTraceExporter(ctx context.Context, cfg config, conn *grpc.ClientConn) (trace.SpanExporter, error) {
+	if cfg.Offline {
+		return stdouttrace.New(stdouttrace.WithWriter(io.Discard))
+	}
+	switch name := cfg.tracesExporter(); name {
+	case "otlp":
+		protocol := cfg.otlpProtocol("traces")
+		if protocol == "grpc" {
+			if conn == nil {
+				return nil, errNoOTLPConn
+			}
+			return otlptracegrpc.New(ctx, otlptracegrpc.WithGRPCConn(conn))
+		}
+		ep, err := otlpHTTPEndpoint(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if protocol == "http/json" {
+			return &otlpJSONSpanExporter{c: newOTLPJSONClient(ep, "traces")}, nil
+		}
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(ep.host), otlptracehttp.WithURLPath(ep.signalPath("traces"))}
+		if ep.insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		} else {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(otlpTLSConfig()))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case "stdout":
+		var opts []stdouttrace.Option
+		switch cmp.Or(cfg.TracesStdoutFormat, cfg.StdoutFormat) {
+		case "otlpjson":
+			return newOTLPJSONExporter(os.Stdout), nil
+		case "pretty":
+			opts = append(opts, stdouttrace.WithPrettyPrint())
+		}
+		return stdouttrace.New(opts...)
+	default:
+		return nil, fmt.Errorf("OTEL_TRACES_EXPORTER: 알 수 없는 익스포터 %q", name)
+	}
+}
+
+// newMetricExporter는 설정된 대상에 맞는 메트릭 익스포터를 만듭니다.
+// 대상이 "none"이면 nil을 반환하며, 제공자에 주기적 내보내기 리더를 붙이지 않습니다.
+// stdout은 추적과 같이 기본은 한 줄 JSON이고, "pretty" 형식일 때만 들여씁니다.
+func newMetricExporter(ctx context.Context, cfg config, conn *grpc.ClientConn) (metric.Exporter, error) {
+	if cfg.Offline {
+		return stdoutmetric.New(stdoutmetric.WithWriter(io.Discard))
+	}
+	switch name := cfg.metricsExporter(); name {
+	case "none":
+		return nil, nil
+	case "otlp":
+		protocol := cfg.otlpProtocol("metrics")
+		if protocol == "grpc" {
+			if conn == nil {
+				return nil, errNoOTLPConn
+			}
+			return otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithGRPCConn(conn))
+		}
+		ep, err := otlpHTTPEndpoint(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if protocol == "http/json" {
+			return &otlpJSONMetricExporter{c: newOTLPJSONClient(ep, "metrics")}, nil
+		}
+		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(ep.host), otlpmetrichttp.WithURLPath(ep.signalPath("metrics"))}
+		if ep.insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		} else {
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(otlpTLSConfig()))
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	case "stdout":
+		var opts []stdoutmetric.Option
+		if cfg.StdoutFormat == "pretty" {
+			opts = append(opts, stdoutmetric.WithPrettyPrint())
+		}
+		return stdoutmetric.New(opts...)
+	default:
+		return nil, fmt.Errorf("OTEL_METRICS_EXPORTER: 알 수 없는 익스포터 %q", name)
+	}
+}
+
+// newLogExporters는 설정된 대상마다 로그 익스포터를 만듭니다.
+// 중간에 실패하면 이미 만든 익스포터를 종료합니다.
+// stdout은 추적과 같이 기본은 한 줄 JSON이고, "pretty" 형식일 때만 들여씁니다.
+func newLogExporters(ctx context.Context, cfg config, conn *grpc.ClientConn) ([]log.Exporter, error) {
+	if cfg.Offline {
+		exporter, err := stdoutlog.New(stdoutlog.WithWriter(io.Discard))
+		if err != nil {
+			return nil, err
+		}
+		return []log.Exporter{exporter}, nil
+	}
+	var exporters []log.Exporter
+	for _, name := range cfg.logsExporters() {
+		var (
+			exporter log.Exporter
+			err      error
+		)
+		switch name {
+		case "otlp":
+			protocol := cfg.otlpProtocol("logs")
+			if protocol == "grpc" {
+				if conn == nil {
+					err = errNoOTLPConn
+					break
+				}
+				exporter, err = otlploggrpc.New(ctx, otlploggrpc.WithGRPCConn(conn))
+				break
+			}
+			var ep otlpEndpoint
+			if ep, err = otlpHTTPEndpoint(cfg); err != nil {
+				break
+			}
+			if protocol == "http/json" {
+				exporter = &otlpJSONLogExporter{c: newOTLPJSONClient(ep, "logs")}
+				break
+			}
+			opts := []otlploghttp.Option{otlploghttp.WithEndpoint(ep.host), otlploghttp.WithURLPath(ep.signalPath("logs"))}
+			if ep.insecure {
+				opts = append(opts, otlploghttp.WithInsecure())
+			} else {
+				opts = append(opts, otlploghttp.WithTLSClientConfig(otlpTLSConfig()))
+			}
+			exporter, err = otlploghttp.New(ctx, opts...)
+		case "stdout":
+			var opts []stdoutlog.Option
+			if cfg.StdoutFormat == "pretty" {
+				opts = append(opts, stdoutlog.WithPrettyPrint())
+			}
+			exporter, err = stdoutlog.New(opts...)
+		default:
+			err = fmt.Errorf("OTEL_LOGS_EXPORTER: 알 수 없는 익스포터 %q", name)
+		}
+		if err != nil {
+			for _, e := range exporters {
+				err = errors.Join(err, e.Shutdown(ctx))
+			}
+			return nil, err
+		}
+		exporters = append(exporters, exporter)
+	}
+	return exporters, nil
+}
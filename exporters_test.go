@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestExporterSelectionConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		wantTraces  string
+		wantMetrics string
+		wantLogs    []string
+		wantErr     string
+	}{
+		{
+			name:       "defaults to stdout",
+			wantTraces: "stdout", wantMetrics: "stdout", wantLogs: []string{"stdout"},
+		},
+		{
+			name:       "endpoint defaults to otlp",
+			env:        map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "localhost:4317"},
+			wantTraces: "otlp", wantMetrics: "otlp", wantLogs: []string{"otlp"},
+		},
+		{
+			name: "per-signal override",
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "localhost:4317",
+				"OTEL_TRACES_EXPORTER":        "stdout",
+				"OTEL_LOGS_EXPORTER":          "stdout,otlp,stdout",
+			},
+			wantTraces: "stdout", wantMetrics: "otlp", wantLogs: []string{"otlp", "stdout"},
+		},
+		{
+			name:    "otlp without endpoint",
+			env:     map[string]string{"OTEL_TRACES_EXPORTER": "otlp"},
+			wantErr: "OTEL_TRACES_EXPORTER=otlp는 OTEL_EXPORTER_OTLP_ENDPOINT가 필요합니다",
+		},
+		{
+			name: "path prefix over grpc",
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_ENDPOINT": "https://gateway.example.com/otlp",
+				"OTEL_EXPORTER_OTLP_PROTOCOL": "grpc",
+			},
+			wantErr: `경로 접두사 "/otlp"가 있는 엔드포인트는 gRPC로 보낼 수 없습니다`,
+		},
+		{
+			name:    "unknown exporter",
+			env:     map[string]string{"OTEL_METRICS_EXPORTER": "zipkin"},
+			wantErr: `OTEL_METRICS_EXPORTER: 알 수 없는 익스포터 "zipkin"`,
+		},
+		{
+			name:    "unknown log exporter",
+			env:     map[string]string{"OTEL_LOGS_EXPORTER": "stdout,kafka"},
+			wantErr: `OTEL_LOGS_EXPORTER: 알 수 없는 익스포터 "kafka"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := loadConfig(nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("에러 = %v, want %q 포함", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadConfig: %v", err)
+			}
+			if got := cfg.tracesExporter(); got != tt.wantTraces {
+				t.Errorf("tracesExporter() = %q, want %q", got, tt.wantTraces)
+			}
+			if got := cfg.metricsExporter(); got != tt.wantMetrics {
+				t.Errorf("metricsExporter() = %q, want %q", got, tt.wantMetrics)
+			}
+			if got := cfg.logsExporters(); !slices.Equal(got, tt.wantLogs) {
+				t.Errorf("logsExporters() = %q, want %q", got, tt.wantLogs)
+			}
+		})
+	}
+}
+
+func TestNewExporters(t *testing.T) {
+	otlpCfg := defaultConfig()
+	otlpCfg.OTLPEndpoint = "localhost:4317"
+	otlpCfg.OTLPInsecure = true
+	// grpc.NewClient는 첫 호출 전까지 연결하지 않으므로 수집기 없이 만들 수 있습니다.
+	conn, err := newOTLPConn(otlpCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	stdoutCfg := defaultConfig()
+	tests := []struct {
+		name     string
+		cfg      config
+		withConn bool
+		wantType string // 익스포터 타입 이름에 들어 있어야 하는 패키지
+		wantErr  error
+	}{
+		{"stdout", stdoutCfg, false, "stdout", nil},
+		{"otlp", otlpCfg, true, "otlp", nil},
+		{"otlp without conn", otlpCfg, false, "", errNoOTLPConn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := conn
+			if !tt.withConn {
+				c = nil
+			}
+			ctx := context.Background()
+			traceExp, traceErr := newTraceExporter(ctx, tt.cfg, c)
+			metricExp, metricErr := newMetricExporter(ctx, tt.cfg, c)
+			logExps, logErr := newLogExporters(ctx, tt.cfg, c)
+			if tt.wantErr != nil {
+				for signal, err := range map[string]error{"traces": traceErr, "metrics": metricErr, "logs": logErr} {
+					if !errors.Is(err, tt.wantErr) {
+						t.Errorf("%s 에러 = %v, want %v", signal, err, tt.wantErr)
+					}
+				}
+				return
+			}
+			if traceErr != nil || metricErr != nil || logErr != nil {
+				t.Fatalf("에러: %v, %v, %v", traceErr, metricErr, logErr)
+			}
+			defer traceExp.Shutdown(ctx)
+			defer metricExp.Shutdown(ctx)
+			types := []string{fmt.Sprintf("%T", traceExp), fmt.Sprintf("%T", metricExp)}
+			for _, e := range logExps {
+				defer e.Shutdown(ctx)
+				types = append(types, fmt.Sprintf("%T", e))
+			}
+			for _, typ := range types {
+				if !strings.Contains(typ, tt.wantType) {
+					t.Errorf("익스포터 타입 %s, want %s 익스포터", typ, tt.wantType)
+				}
+			}
+		})
+	}
+}
+
+func TestOTLPProtocolConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    [3]string // traces, metrics, logs
+		wantErr string
+	}{
+		{
+			name: "grpc by default",
+			env:  map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "localhost:4317"},
+			want: [3]string{"grpc", "grpc", "grpc"},
+		},
+		{
+			name: "path prefix defaults to http/protobuf",
+			env:  map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "https://gateway.example.com/otlp"},
+			want: [3]string{"http/protobuf", "http/protobuf", "http/protobuf"},
+		},
+		{
+			name: "per-signal override",
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_ENDPOINT":         "http://localhost:4318",
+				"OTEL_EXPORTER_OTLP_PROTOCOL":         "http/protobuf",
+				"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL":  "http/json",
+				"OTEL_EXPORTER_OTLP_METRICS_PROTOCOL": "grpc",
+			},
+			want: [3]string{"http/json", "grpc", "http/protobuf"},
+		},
+		{
+			name:    "unknown protocol",
+			env:     map[string]string{"OTEL_EXPORTER_OTLP_LOGS_PROTOCOL": "thrift"},
+			wantErr: `OTEL_EXPORTER_OTLP_LOGS_PROTOCOL: 알 수 없는 프로토콜 "thrift"`,
+		},
+		{
+			name: "per-signal grpc with path prefix",
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_ENDPOINT":      "https://gateway.example.com/otlp",
+				"OTEL_EXPORTER_OTLP_LOGS_PROTOCOL": "grpc",
+			},
+			wantErr: `logs의 OTLP 프로토콜: 경로 접두사 "/otlp"가 있는 엔드포인트는 gRPC로 보낼 수 없습니다`,
+		},
+		{
+			name: "readiness check without grpc",
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_ENDPOINT":     "http://localhost:4318",
+				"OTEL_EXPORTER_OTLP_PROTOCOL":     "http/protobuf",
+				"OTEL_SAMPLE_READY_REQUIRES_OTLP": "true",
+			},
+			wantErr: "OTEL_SAMPLE_READY_REQUIRES_OTLP는 OTLP gRPC 연결이 필요합니다",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := loadConfig(nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("에러 = %v, want %q 포함", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadConfig: %v", err)
+			}
+			got := [3]string{cfg.otlpProtocol("traces"), cfg.otlpProtocol("metrics"), cfg.otlpProtocol("logs")}
+			if got != tt.want {
+				t.Errorf("otlpProtocol = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOTLPHTTPExporters(t *testing.T) {
+	// 신호마다 고른 OTLP/HTTP 인코딩으로 "/v1/<신호>"에 보냅니다.
+	tests := []struct {
+		protocol        string
+		wantContentType string
+	}{
+		{"http/protobuf", "application/x-protobuf"},
+		{"http/json", "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.protocol, func(t *testing.T) {
+			var (
+				mu     sync.Mutex
+				bodies = map[string][]byte{}
+				types  = map[string]string{}
+			)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				mu.Lock()
+				bodies[r.URL.Path] = body
+				types[r.URL.Path] = r.Header.Get("Content-Type")
+				mu.Unlock()
+				w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+			}))
+			defer srv.Close()
+
+			cfg := defaultConfig()
+			cfg.OTLPEndpoint = srv.URL
+			cfg.OTLPProtocol = tt.protocol
+			ctx := context.Background()
+
+			traceExp, err := newTraceExporter(ctx, cfg, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer traceExp.Shutdown(ctx)
+			traceID := trace.TraceID{1, 2, 3}
+			spans := tracetest.SpanStubs{{
+				Name:        "http-roll",
+				SpanContext: trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: trace.SpanID{4}}),
+			}}
+			if err := traceExp.ExportSpans(ctx, spans.Snapshots()); err != nil {
+				t.Errorf("ExportSpans: %v", err)
+			}
+
+			metricExp, err := newMetricExporter(ctx, cfg, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer metricExp.Shutdown(ctx)
+			rm := &metricdata.ResourceMetrics{
+				Resource: resource.Empty(),
+				ScopeMetrics: []metricdata.ScopeMetrics{{Metrics: []metricdata.Metrics{{
+					Name: "dice.rolls",
+					Data: metricdata.Sum[int64]{
+						Temporality: metricdata.CumulativeTemporality,
+						IsMonotonic: true,
+						DataPoints:  []metricdata.DataPoint[int64]{{Value: 3}},
+					},
+				}}}},
+			}
+			if err := metricExp.Export(ctx, rm); err != nil {
+				t.Errorf("메트릭 Export: %v", err)
+			}
+
+			logExps, err := newLogExporters(ctx, cfg, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var rec sdklog.Record
+			rec.SetBody(otellog.StringValue("http-log"))
+			for _, e := range logExps {
+				defer e.Shutdown(ctx)
+				if err := e.Export(ctx, []sdklog.Record{rec}); err != nil {
+					t.Errorf("로그 Export: %v", err)
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for path, want := range map[string]string{"/v1/traces": "http-roll", "/v1/metrics": "dice.rolls", "/v1/logs": "http-log"} {
+				if types[path] != tt.wantContentType {
+					t.Errorf("%s Content-Type = %q, want %q", path, types[path], tt.wantContentType)
+				}
+				if !bytes.Contains(bodies[path], []byte(want)) {
+					t.Errorf("%s 본문에 %q가 없습니다: %q", path, want, bodies[path])
+				}
+			}
+			if tt.protocol == "http/json" && !bytes.Contains(bodies["/v1/traces"], []byte(`"traceId":"`+traceID.String()+`"`)) {
+				t.Errorf("traceId가 16진수가 아닙니다: %s", bodies["/v1/traces"])
+			}
+		})
+	}
+}
+
+func TestOTLPHTTPJSONError(t *testing.T) {
+	// 수집기가 거절하면 상태와 본문을 담은 에러를 돌려주어 배치 프로세서가 실패로 셉니다.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	cfg := defaultConfig()
+	cfg.OTLPEndpoint = srv.URL
+	cfg.OTLPProtocol = "http/json"
+
+	exp, err := newTraceExporter(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer exp.Shutdown(context.Background())
+	err = exp.ExportSpans(context.Background(), tracetest.SpanStubs{{Name: "rejected"}}.Snapshots())
+	if err == nil || !strings.Contains(err.Error(), "429") || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Errorf("ExportSpans 에러 = %v", err)
+	}
+}
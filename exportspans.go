@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// tracedSpanExporter는 배치 내보내기마다 걸린 시간과 배치 크기를 담은 스팬을 남깁니다.
+//
+// 이 스팬은 별도의 TracerProvider가 감싸지 않은 원래 익스포터로 동기적으로 내보내므로
+// 내보내기 스팬이 다시 내보내기 스팬을 만드는 일은 없습니다. 또한 익스포터 내부 계측이
+// 자식 스팬을 만들지 않도록, 익스포터에는 표본 추출되지 않은 부모 컨텍스트를 넘깁니다.
+type tracedSpanExporter struct {
+	next     trace.SpanExporter
+	provider *trace.TracerProvider
+	tracer   oteltrace.Tracer
+}
+
+// res는 앱 제공자와 같은 리소스로, 내보내기 스팬이 같은 service.name 아래에 나타나게 합니다.
+func newTracedSpanExporter(next trace.SpanExporter, res *resource.Resource) *tracedSpanExporter {
+	provider := trace.NewTracerProvider(
+		trace.WithResource(res),
+		trace.WithSyncer(keepOpenSpanExporter{next}),
+	)
+	return &tracedSpanExporter{
+		next:     next,
+		provider: provider,
+		tracer:   provider.Tracer(name + "/export"),
+	}
+}
+
+func (e *tracedSpanExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	ctx, span := e.tracer.Start(ctx, "export spans",
+		oteltrace.WithNewRoot(),
+		oteltrace.WithAttributes(attribute.Int("export.batch.size", len(spans))))
+	defer span.End()
+
+	sc := span.SpanContext()
+	ctx = oteltrace.ContextWithSpanContext(ctx, sc.WithTraceFlags(sc.TraceFlags()&^oteltrace.FlagsSampled))
+	err := e.next.ExportSpans(ctx, spans)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// Shutdown은 남은 내보내기 스팬을 먼저 내보낸 뒤 원래 익스포터를 종료합니다.
+func (e *tracedSpanExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.provider.Shutdown(ctx), e.next.Shutdown(ctx))
+}
+
+// keepOpenSpanExporter는 Shutdown을 전달하지 않아
+// 같은 익스포터가 두 번 종료되지 않게 합니다.
+type keepOpenSpanExporter struct {
+	trace.SpanExporter
+}
+
+func (keepOpenSpanExporter) Shutdown(context.Context) error { return nil }
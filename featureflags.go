@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// maxFeatureFlagAttributes는 스팬에 기록할 수 있는 기능 플래그 수의 상한입니다.
+const maxFeatureFlagAttributes = 16
+
+type featureFlagsKey struct{}
+
+// withFeatureFlags는 기능 플래그 평가 결과를 담은 컨텍스트를 반환합니다.
+// 부모 컨텍스트의 플래그는 그대로 두고 복사본에 values를 더합니다.
+func withFeatureFlags(ctx context.Context, values map[string]attribute.Value) context.Context {
+	flags := maps.Clone(featureFlags(ctx))
+	if flags == nil {
+		flags = make(map[string]attribute.Value, len(values))
+	}
+	maps.Copy(flags, values)
+	return context.WithValue(ctx, featureFlagsKey{}, flags)
+}
+
+// featureFlags는 컨텍스트에 담긴 기능 플래그 평가 결과를 반환합니다.
+// 반환한 맵은 수정하면 안 됩니다.
+func featureFlags(ctx context.Context) map[string]attribute.Value {
+	flags, _ := ctx.Value(featureFlagsKey{}).(map[string]attribute.Value)
+	return flags
+}
+
+// featureFlagProcessor는 스팬이 시작될 때 부모 컨텍스트의 기능 플래그 중
+// 허용된 것만 "feature.<이름>" 속성으로 붙입니다.
+// 실험 플랫폼의 플래그가 많아도 속성 수는 허용 목록 크기로 제한됩니다.
+type featureFlagProcessor struct {
+	flags []string
+}
+
+func newFeatureFlagProcessor(flags []string) *featureFlagProcessor {
+	return &featureFlagProcessor{flags: flags}
+}
+
+func (p *featureFlagProcessor) OnStart(parent context.Context, s trace.ReadWriteSpan) {
+	values := featureFlags(parent)
+	if len(values) == 0 {
+		return
+	}
+	for _, flag := range p.flags {
+		if v, ok := values[flag]; ok {
+			s.SetAttributes(attribute.KeyValue{Key: attribute.Key("feature." + flag), Value: v})
+		}
+	}
+}
+
+func (p *featureFlagProcessor) OnEnd(trace.ReadOnlySpan)         {}
+func (p *featureFlagProcessor) Shutdown(context.Context) error   { return nil }
+func (p *featureFlagProcessor) ForceFlush(context.Context) error { return nil }
+
+// featureFlagHandler는 설정에 고정된 기능 플래그를 요청 컨텍스트에 담습니다.
+// 서버 스팬에도 속성이 붙도록 HTTP 계측보다 바깥에 둬야 합니다.
+// "true", "false"처럼 불리언으로 읽히는 값은 불리언 속성이 됩니다.
+func featureFlagHandler(flags map[string]string, next http.Handler) http.Handler {
+	values := make(map[string]attribute.Value, len(flags))
+	for flag, v := range flags {
+		if b, err := strconv.ParseBool(v); err == nil {
+			values[flag] = attribute.BoolValue(b)
+		} else {
+			values[flag] = attribute.StringValue(v)
+		}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(withFeatureFlags(r.Context(), values)))
+	})
+}
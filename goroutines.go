@@ -0,0 +1,49 @@
+package main
+
+import (
+	"net/http"
+	"runtime"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	goroutineGauge metric.Int64Gauge
+	goroutineDelta metric.Int64Histogram
+)
+
+func init() {
+	var err error
+	goroutineGauge, err = meter.Int64Gauge("http.server.goroutines",
+		metric.WithDescription("요청이 끝난 시점의 프로세스 전체 고루틴 수"),
+		metric.WithUnit("{goroutine}"))
+	if err != nil {
+		panic(err)
+	}
+	goroutineDelta, err = meter.Int64Histogram("http.server.goroutine.delta",
+		metric.WithDescription("요청 처리 전후로 늘어난 고루틴 수(근사치)"),
+		metric.WithUnit("{goroutine}"),
+		metric.WithExplicitBucketBoundaries(0, 1, 2, 5, 10, 50, 100))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// countGoroutines는 요청 처리 전후의 runtime.NumGoroutine 차이를 기록합니다.
+//
+// NumGoroutine은 프로세스 전체 값이므로 동시에 처리 중인 다른 요청이나
+// 백그라운드 작업의 고루틴도 함께 셉니다. 따라서 하나의 값은 의미가 없고,
+// 특정 경로에서 양의 차이가 꾸준히 쌓이는지를 보고 누수를 의심하는 용도입니다.
+// 줄어든 경우는 누수와 관계없으므로 0으로 기록합니다.
+func countGoroutines(route string, next http.Handler) http.Handler {
+	attrs := metric.WithAttributes(attribute.String("http.route", route))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		before := runtime.NumGoroutine()
+		next.ServeHTTP(w, r)
+		after := runtime.NumGoroutine()
+
+		goroutineGauge.Record(r.Context(), int64(after), attrs)
+		goroutineDelta.Record(r.Context(), int64(max(after-before, 0)), attrs)
+	})
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go-opentelemetry-sample/dicepb"
+)
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative dicepb/dice.proto
+
+// maxStreamRolls는 RollDiceStream 요청 하나가 던질 수 있는 최대 횟수입니다.
+const maxStreamRolls = 100
+
+// diceServer는 gRPC DiceService 구현입니다. HTTP 핸들러와 같은 diceHandler로 던지므로
+// roll 스팬, dice.* 메트릭, 던지기 기록이 HTTP 경로와 같습니다.
+type diceServer struct {
+	dicepb.UnimplementedDiceServiceServer
+	dice *diceHandler
+}
+
+// newGRPCServer는 DiceService를 등록한 gRPC 서버를 만듭니다.
+//
+// otelgrpc 통계 핸들러가 RPC마다 서버 스팬과 rpc.server.* 메트릭을 만들고 들어오는 메타데이터에서
+// 트레이스 컨텍스트와 배기지를 꺼냅니다. 제공자와 전파기를 지정하지 않으므로 setupOTelSDK가
+// 설정한 전역 제공자, 즉 HTTP 서버와 같은 TracerProvider와 MeterProvider를 씁니다.
+//
+// 서버 스팬과 메트릭은 핸들러가 끝난 뒤에 기록되므로, 종료할 때 제공자보다 먼저 모두 기록되도록
+// GracefulStop과 Stop이 핸들러가 끝날 때까지 기다리게 합니다(WaitForHandlers).
+func newGRPCServer(dice *diceHandler) *grpc.Server {
+	s := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.WaitForHandlers(true),
+	)
+	dicepb.RegisterDiceServiceServer(s, &diceServer{dice: dice})
+	return s
+}
+
+// RollDice는 주사위를 한 번 던집니다.
+func (s *diceServer) RollDice(ctx context.Context, req *dicepb.RollDiceRequest) (*dicepb.RollDiceResponse, error) {
+	start := time.Now()
+	player, err := s.dice.playerName(req.GetPlayer())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	var resp *dicepb.RollDiceResponse
+	s.dice.throw(ctx, start, player, func(roll int) error {
+		resp = &dicepb.RollDiceResponse{Value: int32(roll)}
+		return nil
+	})
+	return resp, nil
+}
+
+// RollDiceStream은 주사위를 count번 던져 값을 하나씩 보냅니다.
+// 던지기마다 RPC 스팬 아래에 roll 스팬이 하나씩 생깁니다. 클라이언트가 끊으면 Send가
+// 실패하므로 남은 던지기는 하지 않습니다.
+func (s *diceServer) RollDiceStream(req *dicepb.RollDiceStreamRequest, stream grpc.ServerStreamingServer[dicepb.RollDiceResponse]) error {
+	player, err := s.dice.playerName(req.GetPlayer())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if n := req.GetCount(); n < 1 || n > maxStreamRolls {
+		return status.Errorf(codes.InvalidArgument, "count는 1~%d 사이여야 합니다: %d", maxStreamRolls, n)
+	}
+	for range req.GetCount() {
+		err := s.dice.throw(stream.Context(), time.Now(), player, func(roll int) error {
+			return stream.Send(&dicepb.RollDiceResponse{Value: int32(roll)})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shutdownGRPCServer는 진행 중인 RPC를 최대 timeout(0이면 무제한)까지 기다려 s를 종료합니다.
+// 마감 시간이 지나면 Stop으로 남은 RPC를 끊고 errForcedShutdown을 감싼 에러를 반환합니다.
+// s가 nil이면 아무 일도 하지 않습니다.
+func shutdownGRPCServer(s *grpc.Server, addr string, timeout time.Duration) error {
+	if s == nil {
+		return nil
+	}
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+	if timeout <= 0 {
+		<-done
+		return nil
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		s.Stop()
+		<-done
+		return fmt.Errorf("%s: %w (%v)", addr, errForcedShutdown, timeout)
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"go-opentelemetry-sample/dicepb"
+)
+
+// startTestGRPCServer는 s를 메모리 연결에서 시작하고, 계측된 클라이언트와 Serve가 끝나면 닫히는 채널을 반환합니다.
+func startTestGRPCServer(t *testing.T, s *grpc.Server) (dicepb.DiceServiceClient, <-chan error) {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ln) }()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		s.Stop()
+	})
+	return dicepb.NewDiceServiceClient(conn), done
+}
+
+// findRPCSpan은 이름이 spanName이고 종류가 kind인 마지막 스팬을 찾고, 없으면 테스트를 멈춥니다.
+func findRPCSpan(t *testing.T, spanName string, kind trace.SpanKind) tracetest.SpanStub {
+	t.Helper()
+	spans := endedSpans()
+	for i := len(spans) - 1; i >= 0; i-- {
+		if spans[i].Name == spanName && spans[i].SpanKind == kind {
+			return spans[i]
+		}
+	}
+	t.Fatalf("%s 종류의 %q 스팬이 없습니다", kind, spanName)
+	return tracetest.SpanStub{}
+}
+
+func TestGRPCRollDice(t *testing.T) {
+	resetTelemetry(t)
+	s := newGRPCServer(newDiceHandler(defaultConfig(), testDiceInstruments(), testRollStore()))
+	client, _ := startTestGRPCServer(t, s)
+	playerAttr := attribute.String("player", "grpc-player")
+	methodAttr := attribute.String("rpc.method", "RollDice")
+	beforeRolls := counterValue(t, "dice.rolls", playerAttr)
+	beforeRPCs := histogramCount(t, "rpc.server.duration", methodAttr)
+
+	resp, err := client.RollDice(context.Background(), &dicepb.RollDiceRequest{Player: "grpc-player"})
+	if err != nil {
+		t.Fatalf("RollDice: %v", err)
+	}
+	if v := resp.GetValue(); v < 1 || v > 6 {
+		t.Fatalf("값 = %d, want 1~6", v)
+	}
+	// 핸들러가 끝난 뒤 기록되는 서버 스팬과 메트릭까지 남도록 종료를 기다립니다.
+	if err := shutdownGRPCServer(s, "bufnet", time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// 클라이언트도 같은 제공자를 쓰므로 같은 이름의 클라이언트 스팬이 함께 남습니다.
+	server := findRPCSpan(t, "dice.v1.DiceService/RollDice", trace.SpanKindServer)
+	assertSpanAttr(t, server, "rpc.system", attribute.StringValue("grpc"))
+	// 클라이언트 스팬의 컨텍스트가 메타데이터로 전파되어 서버 스팬이 그 자식이 됩니다.
+	if server.Parent.SpanID() != findRPCSpan(t, "dice.v1.DiceService/RollDice", trace.SpanKindClient).SpanContext.SpanID() {
+		t.Error("서버 스팬의 부모가 클라이언트 스팬이 아닙니다")
+	}
+	roll := findSpan(t, "roll")
+	assertSpanAttr(t, roll, "roll.value", attribute.IntValue(int(resp.GetValue())))
+	if roll.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Error("roll 스팬의 부모가 gRPC 서버 스팬이 아닙니다")
+	}
+
+	if got := counterValue(t, "dice.rolls", playerAttr) - beforeRolls; got != 1 {
+		t.Errorf("dice.rolls 증가량 = %d, want 1", got)
+	}
+	if got := histogramCount(t, "rpc.server.duration", methodAttr) - beforeRPCs; got != 1 {
+		t.Errorf("rpc.server.duration 기록 증가량 = %d, want 1", got)
+	}
+}
+
+func TestGRPCRollDiceStream(t *testing.T) {
+	resetTelemetry(t)
+	s := newGRPCServer(newDiceHandler(defaultConfig(), testDiceInstruments(), nil))
+	client, _ := startTestGRPCServer(t, s)
+	methodAttr := attribute.String("rpc.method", "RollDiceStream")
+	beforeRPCs := histogramCount(t, "rpc.server.duration", methodAttr)
+
+	stream, err := client.RollDiceStream(context.Background(), &dicepb.RollDiceStreamRequest{Player: "grpc-stream", Count: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values []int32
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		values = append(values, resp.GetValue())
+	}
+	if len(values) != 3 {
+		t.Fatalf("받은 값 %d개, want 3", len(values))
+	}
+	if err := shutdownGRPCServer(s, "bufnet", time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	server := findRPCSpan(t, "dice.v1.DiceService/RollDiceStream", trace.SpanKindServer).SpanContext
+	// 던지기마다 스트림의 서버 스팬 아래에 roll 스팬이 하나씩 생깁니다.
+	var rolls int
+	for _, sp := range endedSpans() {
+		if sp.Name == "roll" && sp.Parent.SpanID() == server.SpanID() {
+			rolls++
+		}
+	}
+	if rolls != 3 {
+		t.Errorf("스트림 아래 roll 스팬 %d개, want 3", rolls)
+	}
+	if got := histogramCount(t, "rpc.server.duration", methodAttr) - beforeRPCs; got != 1 {
+		t.Errorf("rpc.server.duration 기록 증가량 = %d, want 1", got)
+	}
+}
+
+func TestGRPCInvalidArgument(t *testing.T) {
+	client, _ := startTestGRPCServer(t, newGRPCServer(newDiceHandler(defaultConfig(), testDiceInstruments(), nil)))
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"control character", func() error {
+			_, err := client.RollDice(ctx, &dicepb.RollDiceRequest{Player: "eve\nlog"})
+			return err
+		}},
+		{"zero count", func() error {
+			stream, err := client.RollDiceStream(ctx, &dicepb.RollDiceStreamRequest{})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}},
+		{"too many", func() error {
+			stream, err := client.RollDiceStream(ctx, &dicepb.RollDiceStreamRequest{Count: maxStreamRolls + 1})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(tt.call()); code != codes.InvalidArgument {
+				t.Errorf("코드 = %v, want InvalidArgument", code)
+			}
+		})
+	}
+}
+
+func TestShutdownGRPCServer(t *testing.T) {
+	tests := []struct {
+		name       string
+		slow       bool
+		wantForced bool
+	}{
+		{"idle", false, false},
+		{"in-flight RPC past the deadline", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			defer close(release)
+			started := make(chan struct{})
+			// 끝나지 않는 RPC를 흉내 내도록 인터셉터에서 멈춥니다. 강제 종료로 연결이 끊기면 돌아옵니다.
+			s := grpc.NewServer(grpc.WaitForHandlers(true), grpc.UnaryInterceptor(
+				func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+					close(started)
+					select {
+					case <-release:
+					case <-ctx.Done():
+					}
+					return handler(ctx, req)
+				}))
+			dicepb.RegisterDiceServiceServer(s, &diceServer{dice: newDiceHandler(defaultConfig(), testDiceInstruments(), nil)})
+			client, done := startTestGRPCServer(t, s)
+
+			if tt.slow {
+				go client.RollDice(context.Background(), &dicepb.RollDiceRequest{})
+				<-started
+			}
+
+			err := shutdownGRPCServer(s, "bufnet", 50*time.Millisecond)
+			if got := errors.Is(err, errForcedShutdown); got != tt.wantForced {
+				t.Errorf("shutdownGRPCServer() = %v, 강제 종료 %v, want %v", err, got, tt.wantForced)
+			}
+			select {
+			case err := <-done:
+				// Serve가 시작되기 전에 멈추면 ErrServerStopped가 나옵니다.
+				if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+					t.Errorf("Serve() = %v, want nil", err)
+				}
+			case <-time.After(time.Second):
+				t.Error("gRPC 서버가 멈추지 않았습니다")
+			}
+		})
+	}
+
+	if err := shutdownGRPCServer(nil, "", time.Second); err != nil {
+		t.Errorf("shutdownGRPCServer(nil) = %v", err)
+	}
+}
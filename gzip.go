@@ -0,0 +1,108 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// gzipHandler는 클라이언트가 허용하면 응답을 gzip으로 압축합니다.
+// level은 1(빠름)~9(작음)이며, gzip.Writer는 sync.Pool로 재사용해
+// 처리량이 높을 때의 할당 부담을 줄입니다.
+// 핸들러가 이미 Content-Encoding을 설정한 응답(예: promhttp)은 그대로 둡니다.
+func gzipHandler(level int, next http.Handler) http.Handler {
+	pool := &sync.Pool{
+		New: func() any {
+			// level은 설정 검증을 거쳤으므로 에러가 나지 않습니다.
+			zw, _ := gzip.NewWriterLevel(io.Discard, level)
+			return zw
+		},
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		gw := &gzipResponseWriter{ResponseWriter: w, pool: pool}
+		defer gw.close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		if name, _, _ := strings.Cut(strings.TrimSpace(enc), ";"); name == "gzip" {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter는 첫 쓰기 시점에 압축 여부를 결정합니다.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	pool        *sync.Pool
+	zw          *gzip.Writer
+	wroteHeader bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	h := w.Header()
+	if h.Get("Content-Encoding") == "" && status != http.StatusNoContent && status != http.StatusNotModified {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		w.zw = w.pool.Get().(*gzip.Writer)
+		w.zw.Reset(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		// 압축된 본문으로는 Content-Type을 추측할 수 없으므로 미리 설정합니다.
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.zw == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.zw.Write(b)
+}
+
+// Flush는 아직 헤더를 보내지 않았으면 압축 헤더와 함께 먼저 보냅니다. 그렇지 않으면
+// Content-Encoding 없이 헤더가 나간 뒤 압축된 본문이 이어져 클라이언트가 읽을 수 없습니다.
+func (w *gzipResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.zw != nil {
+		_ = w.zw.Flush()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *gzipResponseWriter) close() {
+	if w.zw == nil {
+		return
+	}
+	_ = w.zw.Close()
+	w.zw.Reset(io.Discard)
+	w.pool.Put(w.zw)
+	w.zw = nil
+}
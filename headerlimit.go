@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var headerRejectCnt metric.Int64Counter
+
+func init() {
+	var err error
+	headerRejectCnt, err = meter.Int64Counter("http.server.header_rejections",
+		metric.WithDescription("헤더가 MaxHeaderBytes를 넘어 431로 거절된 요청 수"),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// status431은 net/http가 헤더 크기 초과 시 연결에 직접 쓰는 응답의 시작 부분입니다.
+var status431 = []byte("HTTP/1.1 431 ")
+
+// headerRejectListener는 431 거절을 세기 위해 연결의 쓰기를 지켜봅니다.
+//
+// net/http는 헤더가 너무 크면 핸들러를 호출하지 않고 연결에 바로 431을 쓰므로
+// HTTP 계측으로는 볼 수 없습니다. TLS 연결은 이 리스너 위에서 암호화되므로
+// 평문 HTTP 서버에서만 셀 수 있습니다.
+type headerRejectListener struct {
+	net.Listener
+}
+
+func (l headerRejectListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return headerRejectConn{c}, nil
+}
+
+type headerRejectConn struct {
+	net.Conn
+}
+
+func (c headerRejectConn) Write(p []byte) (int, error) {
+	if bytes.HasPrefix(p, status431) {
+		headerRejectCnt.Add(context.Background(), 1,
+			metric.WithAttributes(attribute.Int("http.response.status_code", 431)))
+	}
+	return c.Conn.Write(p)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// readiness는 서버가 트래픽을 받을 준비가 되었는지를 나타냅니다.
+// 프로브 핸들러, 시작 대기 고루틴, run이 동시에 읽고 쓰므로 상태를 원자적으로 바꿉니다.
+type readiness struct {
+	// state는 readinessNotReady, readinessReady, readinessShuttingDown 중 하나입니다.
+	state atomic.Int32
+
+	// checks는 준비 상태일 때 추가로 확인할 검사입니다. 서버를 띄우기 전에만
+	// addCheck로 등록하고 이후에는 읽기만 하므로 잠금이 필요 없습니다.
+	checks []readinessCheck
+}
+
+type readinessCheck struct {
+	name  string
+	check func() error
+}
+
+const (
+	readinessNotReady int32 = iota
+	readinessReady
+	readinessShuttingDown
+)
+
+// set은 준비 상태를 바꿉니다. shutdown이 호출된 뒤에는 아무것도 바꾸지 않으므로,
+// 종료가 시작된 뒤 늦게 끝난 시작 대기가 준비 상태를 되살리지 못합니다.
+func (r *readiness) set(ready bool) {
+	next := readinessNotReady
+	if ready {
+		next = readinessReady
+	}
+	for {
+		cur := r.state.Load()
+		if cur == readinessShuttingDown || r.state.CompareAndSwap(cur, next) {
+			return
+		}
+	}
+}
+
+// shutdown은 준비 상태를 내리고, 이후의 set 호출을 무시하게 합니다.
+func (r *readiness) shutdown() { r.state.Store(readinessShuttingDown) }
+
+// addCheck는 /readyz가 준비 상태를 알리기 전에 통과해야 할 검사를 등록합니다.
+func (r *readiness) addCheck(name string, check func() error) {
+	r.checks = append(r.checks, readinessCheck{name: name, check: check})
+}
+
+// serveHTTP는 /readyz 프로브에 응답합니다. 준비되지 않았거나 검사 중 하나라도
+// 실패하면 503과 실패한 검사 이름을 반환합니다.
+func (r *readiness) serveHTTP(w http.ResponseWriter, _ *http.Request) {
+	if r.state.Load() != readinessReady {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	for _, c := range r.checks {
+		if err := c.check(); err != nil {
+			http.Error(w, fmt.Sprintf("not ready: %s: %v", c.name, err), http.StatusServiceUnavailable)
+			return
+		}
+	}
+	io.WriteString(w, "ok\n")
+}
+
+// serveHealthz는 /healthz 활성(liveness) 프로브에 응답합니다. 프로세스가 요청을 처리할 수
+// 있으면 항상 200을 반환하며, 텔레메트리나 의존 서비스 상태는 보지 않습니다. 그래야 수집기
+// 장애 때문에 파드가 재시작되지 않습니다. 트래픽을 받을지는 /readyz가 정합니다.
+func serveHealthz(w http.ResponseWriter, _ *http.Request) {
+	io.WriteString(w, "ok\n")
+}
+
+// waitForStartup은 서버가 뜬 뒤 준비 상태로 바꾸기 전에 호출됩니다.
+// 설정된 지연 동안 캐시 예열 등을 기다리며, 의존 서비스 확인도 이곳에 추가하면 됩니다.
+// ctx가 이미 취소되었거나 기다리는 중에 취소되면 즉시 에러를 반환합니다.
+func waitForStartup(ctx context.Context, cfg config) error {
+	if cfg.StartupDelay <= 0 {
+		return ctx.Err()
+	}
+	log.Printf("준비 상태로 바꾸기 전에 %v 기다립니다", cfg.StartupDelay)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(cfg.StartupDelay):
+		return nil
+	}
+}
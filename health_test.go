@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProbes(t *testing.T) {
+	tests := []struct {
+		name       string
+		ready      bool
+		check      error
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "readyz before setup", path: "/readyz", wantStatus: http.StatusServiceUnavailable, wantBody: "not ready"},
+		{name: "readyz after setup", ready: true, path: "/readyz", wantStatus: http.StatusOK, wantBody: "ok"},
+		{name: "readyz failing check", ready: true, check: errors.New("collector down"), path: "/readyz", wantStatus: http.StatusServiceUnavailable, wantBody: "not ready: otlp: collector down"},
+		// 활성 프로브는 준비 상태와 상관없이 200입니다.
+		{name: "healthz before setup", path: "/healthz", wantStatus: http.StatusOK, wantBody: "ok"},
+		{name: "healthz failing check", ready: true, check: errors.New("collector down"), path: "/healthz", wantStatus: http.StatusOK, wantBody: "ok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetTelemetry(t)
+			ready := &readiness{}
+			ready.set(tt.ready)
+			if tt.check != nil {
+				ready.addCheck("otlp", func() error { return tt.check })
+			}
+			h := newHTTPHandler(defaultConfig(), ready, testDiceInstruments(), nil)
+
+			w := serve(h, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Errorf("상태 = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != tt.wantBody {
+				t.Errorf("본문 = %q, want %q", got, tt.wantBody)
+			}
+			// 프로브는 otelhttp로 감싸지 않으므로 스팬을 남기지 않습니다.
+			if spans := endedSpans(); len(spans) != 0 {
+				t.Errorf("프로브가 스팬 %d개를 남겼습니다", len(spans))
+			}
+		})
+	}
+}
+
+func TestReadinessShutdown(t *testing.T) {
+	// 종료가 시작되면 다시 준비되지 않은 상태가 됩니다.
+	ready := &readiness{}
+	h := newHTTPHandler(defaultConfig(), ready, testDiceInstruments(), nil)
+	for _, step := range []struct {
+		ready bool
+		want  int
+	}{
+		{false, http.StatusServiceUnavailable},
+		{true, http.StatusOK},
+		{false, http.StatusServiceUnavailable},
+	} {
+		ready.set(step.ready)
+		if w := serve(h, httptest.NewRequest(http.MethodGet, "/readyz", nil)); w.Code != step.want {
+			t.Errorf("ready=%v: 상태 = %d, want %d", step.ready, w.Code, step.want)
+		}
+	}
+}
+
+func TestReadinessShutdownWins(t *testing.T) {
+	// 종료가 시작된 뒤 늦게 끝난 시작 대기가 set(true)를 불러도 준비 상태로 돌아가지 않습니다.
+	ready := &readiness{}
+	h := newHTTPHandler(defaultConfig(), ready, testDiceInstruments(), nil)
+	ready.set(true)
+	ready.shutdown()
+	ready.set(true)
+	if w := serve(h, httptest.NewRequest(http.MethodGet, "/readyz", nil)); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("종료 뒤 상태 = %d, want 503", w.Code)
+	}
+}
+
+func TestWaitForStartupCanceled(t *testing.T) {
+	// 지연이 없어도 이미 취소된 ctx면 준비 상태로 바꾸지 않도록 에러를 반환합니다.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, delay := range []time.Duration{0, time.Hour} {
+		cfg := defaultConfig()
+		cfg.StartupDelay = delay
+		if err := waitForStartup(ctx, cfg); !errors.Is(err, context.Canceled) {
+			t.Errorf("지연 %v: waitForStartup = %v, want context.Canceled", delay, err)
+		}
+	}
+	if err := waitForStartup(context.Background(), defaultConfig()); err != nil {
+		t.Errorf("waitForStartup = %v", err)
+	}
+}
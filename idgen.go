@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// seededIDGenerator는 고정된 시드로 트레이스 ID와 스팬 ID를 만드는 trace.IDGenerator입니다.
+// 테스트와 재현용이며 운영 환경에서 쓰면 안 됩니다.
+//
+// 비율 표본 추출기(OTEL_TRACES_SAMPLER=traceidratio)는 트레이스 ID의 하위 8바이트만으로
+// 결정하므로, 같은 시드에서 같은 순서로 루트 스팬을 만들면 표본 추출 결과도 같습니다.
+// 요청이 동시에 들어오면 어떤 요청이 어떤 ID를 받을지는 순서에 따라 달라집니다.
+type seededIDGenerator struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func newSeededIDGenerator(seed int64) *seededIDGenerator {
+	return &seededIDGenerator{rng: rand.New(rand.NewSource(seed))}
+}
+
+func (g *seededIDGenerator) NewIDs(context.Context) (oteltrace.TraceID, oteltrace.SpanID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var tid oteltrace.TraceID
+	for !tid.IsValid() {
+		binary.BigEndian.PutUint64(tid[:8], g.rng.Uint64())
+		binary.BigEndian.PutUint64(tid[8:], g.rng.Uint64())
+	}
+	return tid, g.newSpanID()
+}
+
+func (g *seededIDGenerator) NewSpanID(context.Context, oteltrace.TraceID) oteltrace.SpanID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.newSpanID()
+}
+
+func (g *seededIDGenerator) newSpanID() oteltrace.SpanID {
+	var sid oteltrace.SpanID
+	for !sid.IsValid() {
+		binary.BigEndian.PutUint64(sid[:], g.rng.Uint64())
+	}
+	return sid
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// sampledRoots는 seed로 ID를 만드는 제공자에서 루트 스팬 n개를 만들고,
+// 트레이스 ID와 표본 추출 여부를 반환합니다.
+func sampledRoots(t *testing.T, seed int64, n int) ([]string, []bool) {
+	t.Helper()
+	cfg := defaultConfig()
+	cfg.TracesSampler = "traceidratio"
+	cfg.TracesSamplerArg = "0.5"
+	sampler, err := newSampler(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp, _ := newRecordingTracerProvider(t, nil,
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithIDGenerator(newSeededIDGenerator(seed)))
+	var (
+		ids     []string
+		sampled []bool
+	)
+	for range n {
+		_, span := tp.Tracer("test").Start(context.Background(), "root")
+		sc := span.SpanContext()
+		ids = append(ids, sc.TraceID().String())
+		sampled = append(sampled, sc.IsSampled())
+		span.End()
+	}
+	return ids, sampled
+}
+
+func TestSeededIDGenerator(t *testing.T) {
+	const n = 64
+	ids, sampled := sampledRoots(t, 7, n)
+
+	tests := []struct {
+		name     string
+		seed     int64
+		wantSame bool
+	}{
+		{"same seed", 7, true},
+		{"different seed", 8, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIDs, gotSampled := sampledRoots(t, tt.seed, n)
+			if got := slices.Equal(gotIDs, ids); got != tt.wantSame {
+				t.Errorf("트레이스 ID 열이 같음 = %v, want %v", got, tt.wantSame)
+			}
+			if tt.wantSame && !slices.Equal(gotSampled, sampled) {
+				t.Error("같은 시드인데 표본 추출 결과가 다릅니다")
+			}
+		})
+	}
+
+	// 비율 0.5에서 64개가 모두 같은 결과면 ID가 고르지 않다는 뜻입니다.
+	kept := 0
+	for _, s := range sampled {
+		if s {
+			kept++
+		}
+	}
+	if kept == 0 || kept == n {
+		t.Errorf("%d개 중 %d개가 표본 추출되었습니다", n, kept)
+	}
+}
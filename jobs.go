@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	jobDuration metric.Float64Histogram
+	jobRuns     metric.Int64Counter
+)
+
+func init() {
+	var err error
+	jobDuration, err = meter.Float64Histogram("app.job.duration",
+		metric.WithDescription("백그라운드 작업 한 번을 실행하는 데 걸린 시간"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.001, 0.01, 0.1, 0.5, 1, 5, 10, 30, 60))
+	if err != nil {
+		panic(err)
+	}
+	jobRuns, err = meter.Int64Counter("app.job.runs",
+		metric.WithDescription("백그라운드 작업 실행 횟수. job.result 속성이 success 또는 failure입니다"),
+		metric.WithUnit("{run}"))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// job은 interval마다 실행할 백그라운드 작업입니다.
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(context.Context) error
+}
+
+// scheduler는 작업마다 고루틴을 두고 주기적으로 실행합니다. 실행마다 "job <name>" 루트
+// 스팬을 만들고 걸린 시간과 결과를 메트릭으로 남기며, 실패하면 스팬에 에러 이벤트를 기록합니다.
+// 요청과 관계없는 작업이므로 요청 트레이스에 섞이지 않게 새 루트로 시작합니다.
+type scheduler struct {
+	jobs []job
+	wg   sync.WaitGroup
+}
+
+func newScheduler(jobs ...job) *scheduler {
+	return &scheduler{jobs: jobs}
+}
+
+// start는 ctx가 취소될 때까지 작업을 예약합니다. 취소되면 새 실행을 시작하지 않지만,
+// 이미 시작한 실행은 취소하지 않고 끝까지 두므로 shutdown으로 기다릴 수 있습니다.
+func (s *scheduler) start(ctx context.Context) {
+	runCtx := context.WithoutCancel(ctx)
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			ticker := time.NewTicker(j.interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					s.runOnce(runCtx, j)
+				}
+			}
+		}()
+	}
+}
+
+func (s *scheduler) runOnce(ctx context.Context, j job) {
+	ctx, span := tracer.Start(ctx, "job "+j.name,
+		trace.WithNewRoot(),
+		trace.WithAttributes(attribute.String("job.name", j.name)))
+	defer span.End()
+
+	start := time.Now()
+	err := j.run(ctx)
+	result := "success"
+	if err != nil {
+		result = "failure"
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.ErrorContext(ctx, "백그라운드 작업 실패", "job.name", j.name, "error", err)
+	}
+	attrs := metric.WithAttributes(attribute.String("job.name", j.name), attribute.String("job.result", result))
+	jobDuration.Record(ctx, time.Since(start).Seconds(), attrs)
+	jobRuns.Add(ctx, 1, attrs)
+}
+
+// shutdown은 예약 고루틴과 진행 중인 실행이 끝나기를 ctx가 끝날 때까지 기다립니다.
+// start에 넘긴 컨텍스트를 먼저 취소해야 합니다. 실행 중에 만든 스팬이 내보내지도록
+// 텔레메트리 제공자를 종료하기 전에 호출합니다.
+func (s *scheduler) shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// rollStatsJob은 누적 던지기 통계(rollStats)를 요약해 로그로 남기는 작업입니다.
+// 메트릭 백엔드 없이 로그만 모으는 환경에서도 추이를 볼 수 있습니다.
+func rollStatsJob(interval time.Duration) job {
+	return job{
+		name:     "roll-stats",
+		interval: interval,
+		run: func(ctx context.Context) error {
+			attrs := make([]slog.Attr, 0, len(rollStats.faces)+1)
+			var total int64
+			for i := range rollStats.faces {
+				n := rollStats.faces[i].Load()
+				total += n
+				attrs = append(attrs, slog.Int64("dice.rolls.face."+strconv.Itoa(i+1), n))
+			}
+			attrs = append(attrs, slog.Int64("dice.rolls.total", total))
+			logger.LogAttrs(ctx, slog.LevelInfo, "누적 던지기 통계", attrs...)
+			return nil
+		},
+	}
+}
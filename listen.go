@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"syscall"
+	"time"
+)
+
+// listen은 addr에 TCP 리스너를 엽니다.
+//
+// 롤링 재시작 중에는 이전 프로세스가 포트를 막 놓은 참이라 잠깐 EADDRINUSE가 날 수 있습니다.
+// attempts가 1보다 크면 이 경우에만 delay부터 두 배씩 늘려 가며 다시 시도합니다.
+// 그 밖의 에러(권한, 잘못된 주소 등)는 기다려도 나아지지 않으므로 바로 반환합니다.
+func listen(ctx context.Context, addr string, attempts int, delay time.Duration) (net.Listener, error) {
+	var lc net.ListenConfig
+	for attempt := 1; ; attempt++ {
+		ln, err := lc.Listen(ctx, "tcp", addr)
+		if err == nil || attempt >= attempts || !errors.Is(err, syscall.EADDRINUSE) {
+			return ln, err
+		}
+		log.Printf("%s 바인딩 실패(%d/%d), %v 뒤 다시 시도합니다: %v", addr, attempt, attempts, delay, err)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+)
+
+// logLevel은 구조화 로거의 최소 심각도입니다. 기본값은 Info이며
+// 관리 모드에서 /admin/loglevel로 재시작 없이 바꿀 수 있습니다.
+var logLevel slog.LevelVar
+
+// levelHandler는 level 미만의 레코드를 버린 뒤 나머지를 다음 slog.Handler로 넘깁니다.
+type levelHandler struct {
+	slog.Handler
+	level slog.Leveler
+}
+
+func newLevelHandler(next slog.Handler, level slog.Leveler) *levelHandler {
+	return &levelHandler{Handler: next, level: level}
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.Handler.Enabled(ctx, level)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return newLevelHandler(h.Handler.WithAttrs(attrs), h.level)
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return newLevelHandler(h.Handler.WithGroup(name), h.level)
+}
+
+// logLevelResponse는 /admin/loglevel의 응답 본문입니다.
+type logLevelResponse struct {
+	Level string `json:"level"`
+}
+
+// getLogLevel은 현재 최소 심각도를 반환합니다.
+func getLogLevel(w http.ResponseWriter, _ *http.Request) {
+	writeLogLevel(w)
+}
+
+// setLogLevel은 level 폼 값(예: "debug", "WARN", "INFO+2")으로 최소 심각도를 바꾸고
+// 바뀐 값을 반환합니다. 알 수 없는 값이면 400을 반환하고 기존 값을 유지합니다.
+func setLogLevel(w http.ResponseWriter, r *http.Request) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(r.FormValue("level"))); err != nil {
+		http.Error(w, fmt.Sprintf("잘못된 로그 수준 %q", r.FormValue("level")), http.StatusBadRequest)
+		return
+	}
+	old := logLevel.Level()
+	logLevel.Set(level)
+	// 새 수준에 따라 걸러지지 않도록 구조화 로거 대신 표준 로거로 남깁니다.
+	log.Printf("로그 수준을 %v에서 %v(으)로 바꿨습니다", old, level)
+	writeLogLevel(w)
+}
+
+func writeLogLevel(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(logLevelResponse{Level: logLevel.Level().String()})
+}
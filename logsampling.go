@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/log"
+)
+
+// severitySamplingProcessor는 심각도 구간별 비율로 로그 레코드를 표본 추출한 뒤
+// 남은 레코드만 다음 Processor로 넘깁니다.
+// Error 이상의 레코드는 설정과 관계없이 항상 통과합니다.
+type severitySamplingProcessor struct {
+	log.Processor
+	ratios map[string]float64
+}
+
+// newSeveritySamplingProcessor는 "trace", "debug", "info", "warn" 구간별 보존 비율
+// (0~1)로 next를 감쌉니다. 비율이 지정되지 않은 구간은 모두 보존합니다.
+func newSeveritySamplingProcessor(next log.Processor, ratios map[string]float64) log.Processor {
+	if len(ratios) == 0 {
+		return next
+	}
+	return &severitySamplingProcessor{Processor: next, ratios: ratios}
+}
+
+func (p *severitySamplingProcessor) OnEmit(ctx context.Context, r *log.Record) error {
+	band := severityBand(r.Severity())
+	if band == "error" {
+		return p.Processor.OnEmit(ctx, r)
+	}
+	if ratio, ok := p.ratios[band]; ok && rand.Float64() >= ratio {
+		return nil
+	}
+	return p.Processor.OnEmit(ctx, r)
+}
+
+// severityBand는 심각도 번호를 설정에서 쓰는 구간 이름으로 바꿉니다.
+func severityBand(s otellog.Severity) string {
+	switch {
+	case s >= otellog.SeverityError1:
+		return "error"
+	case s >= otellog.SeverityWarn1:
+		return "warn"
+	case s >= otellog.SeverityInfo1:
+		return "info"
+	case s >= otellog.SeverityDebug1:
+		return "debug"
+	default:
+		return "trace"
+	}
+}
+
+// severityTextProcessor는 심각도 텍스트가 비어 있는 레코드에 심각도 번호의 짧은 이름
+// ("INFO", "WARN" 등)을 채운 뒤 다음 Processor로 넘깁니다. slog 브리지는 심각도 번호,
+// 관측 시각, 트레이스 컨텍스트(추적/스팬 ID와 trace flags)는 채우지만 텍스트는 비워 두므로,
+// 텍스트로 심각도를 읽는 백엔드에서도 수준이 보이게 합니다.
+//
+// 표준 log 패키지(llog)로 남기는 시작/종료 메시지는 운영자용 stderr 출력이라 OTel 레코드가
+// 되지 않습니다. 백엔드에서 보아야 하는 로그는 ctx와 함께 구조화 로거로 남깁니다.
+type severityTextProcessor struct {
+	log.Processor
+}
+
+func (p severityTextProcessor) OnEmit(ctx context.Context, r *log.Record) error {
+	if r.SeverityText() == "" && r.Severity() != otellog.SeverityUndefined {
+		r.SetSeverityText(r.Severity().String())
+	}
+	return p.Processor.OnEmit(ctx, r)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSeveritySamplingProcessor(t *testing.T) {
+	ratios := map[string]float64{"debug": 0, "info": 1, "warn": 0}
+	tests := []struct {
+		severity otellog.Severity
+		kept     bool
+	}{
+		{otellog.SeverityTrace, true}, // 비율이 없는 구간은 모두 보존합니다.
+		{otellog.SeverityDebug, false},
+		{otellog.SeverityInfo, true},
+		{otellog.SeverityWarn4, false},
+		{otellog.SeverityError, true},
+		{otellog.SeverityFatal, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.severity.String(), func(t *testing.T) {
+			rec := &logRecorder{}
+			p := newSeveritySamplingProcessor(log.NewSimpleProcessor(rec), ratios)
+			var r log.Record
+			r.SetSeverity(tt.severity)
+			if err := p.OnEmit(context.Background(), &r); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(rec.get()) == 1; got != tt.kept {
+				t.Errorf("보존 = %v, want %v", got, tt.kept)
+			}
+		})
+	}
+}
+
+func TestSeveritySamplingProcessorError(t *testing.T) {
+	// Error 구간에 비율을 줘도 Error 이상은 항상 보존합니다.
+	rec := &logRecorder{}
+	p := newSeveritySamplingProcessor(log.NewSimpleProcessor(rec), map[string]float64{"error": 0})
+	var r log.Record
+	r.SetSeverity(otellog.SeverityError)
+	if err := p.OnEmit(context.Background(), &r); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.get()) != 1 {
+		t.Error("Error 레코드가 버려졌습니다")
+	}
+}
+
+func TestLogSamplingConfig(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr string
+	}{
+		{"debug=0.1,info=0.5", ""},
+		{"error=0.5", `알 수 없는 심각도 구간 "error"`},
+		{"info=1.5", "info 비율은 0~1 범위여야 합니다"},
+		{"debug=x", "OTEL_SAMPLE_LOG_SAMPLING"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("OTEL_SAMPLE_LOG_SAMPLING", tt.value)
+			cfg, err := loadConfig(nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("loadConfig: %v", err)
+				}
+				if cfg.LogSampleRatios["debug"] != 0.1 || cfg.LogSampleRatios["info"] != 0.5 {
+					t.Errorf("LogSampleRatios = %v", cfg.LogSampleRatios)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("에러 = %v, want %q 포함", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLogRecordFields(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.LogsProcessor = "simple"
+	rec := &logRecorder{}
+	lp := newLoggerProvider(cfg, []log.Exporter{rec}, resource.Empty())
+	defer lp.Shutdown(context.Background())
+	l := slog.New(otelslog.NewHandler("test", otelslog.WithLoggerProvider(lp)))
+
+	tests := []struct {
+		name         string
+		level        slog.Level
+		sampled      bool
+		wantSeverity otellog.Severity
+		wantText     string
+	}{
+		{"info sampled", slog.LevelInfo, true, otellog.SeverityInfo, "INFO"},
+		{"warn sampled", slog.LevelWarn, true, otellog.SeverityWarn, "WARN"},
+		{"error not sampled", slog.LevelError, false, otellog.SeverityError, "ERROR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec.reset()
+			sampler := sdktrace.AlwaysSample()
+			if !tt.sampled {
+				sampler = sdktrace.NeverSample()
+			}
+			tp, _ := newRecordingTracerProvider(t, nil, sdktrace.WithSampler(sampler))
+			ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+			before := time.Now()
+			l.Log(ctx, tt.level, "주사위를 던졌습니다")
+			span.End()
+
+			records := rec.get()
+			if len(records) != 1 {
+				t.Fatalf("레코드 %d개, want 1", len(records))
+			}
+			r := records[0]
+			if r.Severity() != tt.wantSeverity || r.SeverityText() != tt.wantText {
+				t.Errorf("심각도 = %v %q, want %v %q", r.Severity(), r.SeverityText(), tt.wantSeverity, tt.wantText)
+			}
+			sc := span.SpanContext()
+			if r.TraceID() != sc.TraceID() || r.SpanID() != sc.SpanID() {
+				t.Errorf("트레이스 컨텍스트 = %s/%s, want %s/%s", r.TraceID(), r.SpanID(), sc.TraceID(), sc.SpanID())
+			}
+			if r.TraceFlags() != sc.TraceFlags() || r.TraceFlags().IsSampled() != tt.sampled {
+				t.Errorf("TraceFlags = %v, want %v", r.TraceFlags(), sc.TraceFlags())
+			}
+			if r.ObservedTimestamp().Before(before) || r.Timestamp().IsZero() {
+				t.Errorf("시각 = %v, 관측 시각 = %v", r.Timestamp(), r.ObservedTimestamp())
+			}
+		})
+	}
+}
+
+func TestSeverityTextProcessorKeepsText(t *testing.T) {
+	// 이미 텍스트가 있으면 덮어쓰지 않고, 심각도가 없으면 채우지 않습니다.
+	tests := []struct {
+		severity otellog.Severity
+		text     string
+		want     string
+	}{
+		{otellog.SeverityWarn, "warning", "warning"},
+		{otellog.SeverityDebug2, "", "DEBUG2"},
+		{otellog.SeverityUndefined, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.severity.String(), func(t *testing.T) {
+			rec := &logRecorder{}
+			p := severityTextProcessor{log.NewSimpleProcessor(rec)}
+			var r log.Record
+			r.SetSeverity(tt.severity)
+			r.SetSeverityText(tt.text)
+			if err := p.OnEmit(context.Background(), &r); err != nil {
+				t.Fatal(err)
+			}
+			if got := rec.get()[0].SeverityText(); got != tt.want {
+				t.Errorf("SeverityText = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,15 +3,22 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -25,64 +32,403 @@ func run() (err error) {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
 
+	cfg, err := loadConfig(os.Args[1:])
+	if err != nil {
+		return
+	}
+	if cfg.ReplayFile != "" {
+		return replaySpans(ctx, cfg)
+	}
+
 	// OpenTelemetry 설정
-	otelShutdown, err := setupOTelSDK(ctx)
+	ready := &readiness{}
+	diceInst, otelShutdown, err := setupTelemetry(ctx, cfg, ready, meter)
 	if err != nil {
 		return
 	}
 	// 메모리 누수 방지를 위해 종료를 적절히 처리합니다.
+	// 정상 종료가 시작되었으면 전체 종료 시간을 로그로 남기도록 시작 시각을 넘깁니다.
+	var shutdownStart time.Time
 	defer func() {
-		err = errors.Join(err, otelShutdown(context.Background()))
+		start := time.Now()
+		shutdownCtx := context.Background()
+		if !shutdownStart.IsZero() {
+			shutdownCtx = withShutdownStart(shutdownCtx, shutdownStart)
+		}
+		err = errors.Join(err, otelShutdown(shutdownCtx))
+		if cfg.DebugShutdownSpan {
+			log.Printf("텔레메트리 제공자 종료에 %v 걸렸습니다", time.Since(start))
+		}
 	}()
 
+	// 던지기 기록 저장소. 쿼리 스팬이 내보내지도록 제공자보다 먼저 닫습니다.
+	var store *rollStore
+	if cfg.DatabaseURL != "" {
+		if store, err = openRollStore(ctx, cfg.DatabaseURL); err != nil {
+			return
+		}
+		defer func() { err = errors.Join(err, store.Close()) }()
+	}
+
 	// HTTP 서버 시작
 	srv := &http.Server{
-		Addr:         ":8080",
-		BaseContext:  func(_ net.Listener) context.Context { return ctx },
-		ReadTimeout:  time.Second,
-		WriteTimeout: 10 * time.Second,
-		Handler:      newHTTPHandler(),
+		Addr:           cfg.ListenAddr,
+		BaseContext:    func(_ net.Listener) context.Context { return ctx },
+		ReadTimeout:    time.Second,
+		WriteTimeout:   10 * time.Second,
+		MaxHeaderBytes: cfg.MaxHeaderBytes,
+		Handler:        newHTTPHandler(cfg, ready, diceInst, store),
+	}
+	tlsEnabled := cfg.TLSCertFile != "" && cfg.TLSKeyFile != ""
+	connHooks := []func(net.Conn, http.ConnState){newConnReuseRecorder().connState}
+	if tlsEnabled {
+		connHooks = append(connHooks, (&tlsHandshakeRecorder{}).connState)
+	}
+	srv.ConnState = chainConnState(connHooks...)
+	servers := []*http.Server{srv}
+	shutdownTimeouts := []time.Duration{cfg.ShutdownTimeout}
+
+	// 메트릭 전용 주소가 설정되면 /metrics를 별도 서버에서 제공합니다.
+	// 스크레이프만 처리하므로 앱 서버보다 짧은 종료 제한 시간을 줄 수 있습니다.
+	if cfg.MetricsAddr != "" {
+		servers = append(servers, &http.Server{
+			Addr:         cfg.MetricsAddr,
+			ReadTimeout:  time.Second,
+			WriteTimeout: 10 * time.Second,
+			Handler:      newMetricsHandler(cfg),
+		})
+		shutdownTimeouts = append(shutdownTimeouts, cfg.MetricsShutdownTimeout)
+	}
+
+	// 서버를 띄우기 전에 모든 주소를 바인딩해, 바인딩 실패를 재시도하고 바로 보고합니다.
+	listeners := make([]net.Listener, 0, len(servers))
+	for _, s := range servers {
+		ln, lnErr := listen(ctx, s.Addr, cfg.ListenAttempts, cfg.ListenRetryDelay)
+		if lnErr != nil {
+			for _, ln := range listeners {
+				ln.Close()
+			}
+			return lnErr
+		}
+		if s == srv && !tlsEnabled {
+			ln = headerRejectListener{ln}
+		}
+		listeners = append(listeners, ln)
+	}
+
+	// gRPC 서버는 HTTP 서버와 같은 주사위 계측기와 저장소를 쓰고, 아래에서 함께 종료됩니다.
+	var grpcSrv *grpc.Server
+	var grpcLn net.Listener
+	if cfg.GRPCAddr != "" {
+		grpcLn, err = listen(ctx, cfg.GRPCAddr, cfg.ListenAttempts, cfg.ListenRetryDelay)
+		if err != nil {
+			for _, ln := range listeners {
+				ln.Close()
+			}
+			return
+		}
+		grpcSrv = newGRPCServer(newDiceHandler(cfg, diceInst, store))
+	}
+
+	srvErr := make(chan error, len(servers)+1)
+	for i, s := range servers {
+		go func() {
+			if s == srv && tlsEnabled {
+				srvErr <- s.ServeTLS(listeners[i], cfg.TLSCertFile, cfg.TLSKeyFile)
+				return
+			}
+			srvErr <- s.Serve(listeners[i])
+		}()
+	}
+	if grpcSrv != nil {
+		go func() { srvErr <- grpcSrv.Serve(grpcLn) }()
 	}
-	srvErr := make(chan error, 1)
+
+	// 데모용 합성 트래픽은 플래그로 켰을 때만 보냅니다.
+	if cfg.DemoTrafficInterval > 0 {
+		scheme := "http"
+		if tlsEnabled {
+			scheme = "https"
+		}
+		port := listeners[0].Addr().(*net.TCPAddr).Port
+		baseURL := scheme + "://" + net.JoinHostPort("localhost", strconv.Itoa(port))
+		go generateTraffic(ctx, baseURL, cfg.DemoTrafficInterval, cfg.ClientTracePhases, cfg.DemoTrafficDebug)
+	}
+
+	// 백그라운드 작업은 종료 시그널에 새 실행을 멈추고, 진행 중인 실행은 아래에서 기다립니다.
+	var jobs []job
+	if cfg.RollStatsJobInterval > 0 {
+		jobs = append(jobs, rollStatsJob(cfg.RollStatsJobInterval))
+	}
+	sched := newScheduler(jobs...)
+	sched.start(ctx)
+
+	// 서버는 이미 요청을 받지만, 시작 대기가 끝날 때까지 /readyz는 503을 반환합니다.
 	go func() {
-		srvErr <- srv.ListenAndServe()
+		if waitForStartup(ctx, cfg) == nil {
+			ready.set(true)
+		}
 	}()
 
 	// 인터럽트 대기
+	var st *shutdownTrace
 	select {
 	case err = <-srvErr:
-		// HTTP 서버 시작 시 오류 발생
-		return
+		// HTTP 서버 시작 시 오류 발생. 아래에서 이미 떠 있는 다른 서버도 정리합니다.
+		st = startShutdownTrace(cfg.DebugShutdownSpan, "server_error")
 	case <-ctx.Done():
 		// 첫 번째 CTRL+C 대기
 		// 최대한 빨리 시그널 알림 수신을 중지합니다.
 		stop()
+		log.Println("종료 시그널을 받아 정상 종료를 시작합니다")
+		if cfg.ForceExitOnSecondSignal {
+			forceExitOnSignal()
+		}
+		st = startShutdownTrace(cfg.DebugShutdownSpan, "signal:"+os.Interrupt.String())
 	}
 
-	// Shutdown이 호출되면 ListenAndServe는 즉시 ErrServerClosed를 반환합니다.
-	err = srv.Shutdown(context.Background())
+	shutdownStart = time.Now()
+
+	// 종료가 시작되면 새 트래픽이 오지 않도록 준비 상태부터 내립니다.
+	// 시작 대기 고루틴이 아직 돌고 있어도 다시 준비 상태로 돌아가지 않습니다.
+	ready.shutdown()
+
+	// Shutdown이 호출되면 Serve는 즉시 ErrServerClosed를 반환합니다.
+	// gRPC 서버도 같은 제한 시간으로 동시에 진행 중인 RPC를 기다립니다.
+	err = errors.Join(err, st.phase("drain", func() error {
+		grpcErr := make(chan error, 1)
+		go func() { grpcErr <- shutdownGRPCServer(grpcSrv, cfg.GRPCAddr, cfg.ShutdownTimeout) }()
+		return errors.Join(shutdownServers(servers, shutdownTimeouts), <-grpcErr)
+	}))
+	// 서버 오류로 끝난 경우에도 작업 예약을 멈춥니다. 실행 중인 작업의 스팬이 내보내지도록
+	// 제공자를 종료하기 전에 기다립니다.
+	stop()
+	err = errors.Join(err, st.phase("jobs", func() error {
+		jobsCtx := context.Background()
+		if cfg.ShutdownTimeout > 0 {
+			var cancel context.CancelFunc
+			jobsCtx, cancel = context.WithTimeout(jobsCtx, cfg.ShutdownTimeout)
+			defer cancel()
+		}
+		return sched.shutdown(jobsCtx)
+	}))
+	// 종료 스팬은 제공자가 종료되기 전에 끝나고 내보내져야 하므로 플러시도 여기서 합니다.
+	if st != nil {
+		err = errors.Join(err, st.phase("flush", func() error {
+			return flushTelemetry(context.Background())
+		}))
+		st.end(err)
+	}
 	return
 }
 
-func newHTTPHandler() http.Handler {
+// setupTelemetry는 OpenTelemetry SDK를 설정하고 m으로 주사위 계측기를 만듭니다.
+// SDK 설정 뒤의 단계가 실패하면 이미 시작한 제공자, OTLP 연결, SIGQUIT 감시를 직접 종료하므로,
+// 호출자는 에러가 없을 때만 shutdown을 책임집니다.
+func setupTelemetry(ctx context.Context, cfg config, ready *readiness, m metric.Meter) (*diceInstruments, func(context.Context) error, error) {
+	setupStart := time.Now()
+	shutdown, err := setupOTelSDK(ctx, cfg, ready)
+	if err != nil {
+		return nil, nil, err
+	}
+	setupTime := time.Since(setupStart)
+	log.Printf("OpenTelemetry 설정에 %v 걸렸습니다", setupTime)
+	setupDuration.Record(ctx, setupTime.Seconds())
+
+	diceInst, err := newDiceInstruments(m)
+	if err != nil {
+		return nil, nil, errors.Join(fmt.Errorf("주사위 계측기 생성: %w", err), shutdown(context.Background()))
+	}
+	return diceInst, shutdown, nil
+}
+
+// forceExitOnSignal은 정상 종료 도중 다시 CTRL+C가 들어오면
+// 남은 정리를 기다리지 않고 즉시 프로세스를 끝냅니다.
+func forceExitOnSignal() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	go func() {
+		<-sigCh
+		log.Println("두 번째 시그널을 받아 정상 종료를 기다리지 않고 즉시 종료합니다")
+		// 128 + SIGINT(2): 시그널로 중단된 프로세스의 관례적인 종료 코드입니다.
+		os.Exit(130)
+	}()
+}
+
+// errForcedShutdown은 제한 시간 안에 진행 중인 요청이 끝나지 않아 연결을 강제로 닫았음을 나타냅니다.
+var errForcedShutdown = errors.New("정상 종료 제한 시간이 지나 강제로 종료했습니다")
+
+// shutdownServers는 모든 서버를 동시에 종료하고 에러를 결합합니다.
+// servers[i]는 timeouts[i] 안에 종료되어야 합니다(shutdownServer 참고).
+func shutdownServers(servers []*http.Server, timeouts []time.Duration) error {
+	errs := make([]error, len(servers))
+	var wg sync.WaitGroup
+	for i, s := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = shutdownServer(s, timeouts[i])
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// shutdownServer는 진행 중인 요청을 최대 timeout(0이면 무제한)까지 기다려 s를 종료합니다.
+// 마감 시간이 지나면 남은 연결을 Close로 닫고 errForcedShutdown을 감싼 에러를 반환합니다.
+func shutdownServer(s *http.Server, timeout time.Duration) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	err := s.Shutdown(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = errors.Join(fmt.Errorf("%s: %w (%v)", s.Addr, errForcedShutdown, timeout), s.Close())
+	}
+	return err
+}
+
+func newHTTPHandler(cfg config, ready *readiness, diceInst *diceInstruments, store *rollStore) http.Handler {
 	mux := http.NewServeMux()
+	// routes는 "/"를 뺀 등록 패턴만 담아, 일치하는 경로가 없는 요청을 404와 405로 나눌 때 씁니다.
+	routes := http.NewServeMux()
 
 	// handleFunc는 mux.HandleFunc의 대체 함수로
 	// 핸들러의 HTTP 계측을 http.route로 보강합니다.
-	handleFunc := func(pattern string, handlerFunc func(http.ResponseWriter, *http.Request)) {
+	// metricAttrs는 이 경로의 HTTP 메트릭에만 붙일 속성입니다(withMetricAttributes 참고).
+	handleFunc := func(pattern string, handlerFunc func(http.ResponseWriter, *http.Request), metricAttrs ...attribute.KeyValue) {
+		var handler http.Handler = http.HandlerFunc(handlerFunc)
+		if d := cfg.routeTimeout(pattern); d > 0 {
+			handler = http.TimeoutHandler(handler, d, "요청 처리 시간이 초과되었습니다")
+		}
+		if types := cfg.routeContentTypes(pattern); len(types) > 0 {
+			handler = requireContentType(types, handler)
+		}
+		if cfg.GoroutineMetrics {
+			handler = countGoroutines(pattern, handler)
+		}
+		if cfg.MaxSpansPerTrace > 0 {
+			handler = limitSpans(cfg.MaxSpansPerTrace, handler)
+		}
+		if cfg.ServerSpanName == "route" {
+			handler = nameSpanByRoute(pattern, handler)
+		}
+		if cfg.RoutePatternAttribute {
+			handler = tagRoutePattern(pattern, handler)
+		}
+		handler = withAttributeBag(handler)
+		if len(metricAttrs) > 0 {
+			handler = withMetricAttributes(metricAttrs, handler)
+		}
 		// HTTP 계측을 위한 "http.route" 구성
-		handler := otelhttp.WithRouteTag(pattern, http.HandlerFunc(handlerFunc))
+		handler = otelhttp.WithRouteTag(pattern, handler)
 		mux.Handle(pattern, handler)
+		routes.Handle(pattern, routeProbe{})
 	}
 
 	// 핸들러 등록
-	handleFunc("/rolldice/", rolldice)
-	handleFunc("/rolldice/{player}", rolldice)
+	dice := newDiceHandler(cfg, diceInst, store)
+	handleFunc("/rolldice/", dice.rolldice)
+	handleFunc("/rolldice/{player}", dice.rolldice)
+	handleFunc("/rolldice/versus/{player}", dice.versus)
+	if store != nil {
+		handleFunc("GET /rolldice/history/{player}", dice.history)
+	}
+	// 부하 테스트용 엔드포인트는 켰을 때만 노출합니다. "bench"라는 플레이어 경로를 가립니다.
+	if cfg.Bench {
+		handleFunc("/rolldice/bench", dice.bench, attribute.String("app.feature", "bench"))
+	}
+
+	// 관리 모드에서만 운영용 엔드포인트를 노출합니다.
+	if cfg.Admin {
+		handleFunc("GET /admin/loglevel", getLogLevel)
+		handleFunc("POST /admin/loglevel", setLogLevel)
+		handleFunc("GET /admin/cardinality", serveCardinality)
+		handleFunc("GET /admin/stats", serveRollStats)
+		handleFunc("GET /debug/otel", serveOTelStatus)
+		if cfg.AdminTraces {
+			handleFunc("GET /admin/traces", serveRecentTraces)
+		}
+	}
+
+	// 일치하는 경로가 없는 요청은 http.route="not_found"로 계측합니다.
+	// "/" 패턴은 가장 덜 구체적이므로 위의 경로들을 가리지 않습니다.
+	// 경로는 있지만 메서드가 다르면 404 대신 Allow 헤더와 함께 405를 응답합니다.
+	var notFoundHandler http.Handler = notFoundOrNotAllowed(routes)
+	if cfg.ServerSpanName == "route" {
+		notFoundHandler = nameSpanByRoute("", notFoundHandler)
+	}
+	mux.Handle("/", otelhttp.WithRouteTag("not_found", notFoundHandler))
 
 	// Prometheus metrics 엔드포인트 추가
-	mux.Handle("/metrics", promhttp.Handler())
+	// 별도 메트릭 서버를 쓰면 그쪽에서만 제공합니다.
+	if cfg.MetricsAddr == "" && cfg.Prometheus {
+		mux.Handle("/metrics", newPrometheusHandler())
+		routes.Handle("/metrics", routeProbe{})
+	}
+
+	var handler http.Handler = mux
+	if cfg.GzipLevel > 0 {
+		handler = gzipHandler(cfg.GzipLevel, handler)
+	}
 
 	// 전체 서버에 대한 HTTP 계측 추가
-	handler := otelhttp.NewHandler(mux, "/")
+	// 건너뛸 User-Agent는 서버 스팬과 HTTP 메트릭을 만들지 않고(WithFilter), 자식 스팬도 억제합니다.
+	var otelOpts []otelhttp.Option
+	skipUA := userAgentMatcher(cfg.SkipUserAgents)
+	if len(cfg.SkipUserAgents) > 0 {
+		otelOpts = append(otelOpts, otelhttp.WithFilter(func(r *http.Request) bool { return !skipUA(r) }))
+	}
+	handler = otelhttp.NewHandler(handler, "/", otelOpts...)
+	if len(cfg.SkipUserAgents) > 0 {
+		handler = suppressTracingFor(skipUA, handler)
+	}
+
+	// 프로브 트래픽이 스팬과 메트릭을 어지럽히지 않도록 계측 바깥에서 처리합니다.
+	probes := http.NewServeMux()
+	probes.HandleFunc("/healthz", serveHealthz)
+	probes.HandleFunc("/readyz", ready.serveHTTP)
+	probes.Handle("/", handler)
+	handler = probes
+
+	handler = protectPaths(cfg, handler)
+	if len(cfg.FeatureFlags) > 0 {
+		handler = featureFlagHandler(cfg.FeatureFlags, handler)
+	}
+	if cfg.TenantAttributesFile != "" {
+		handler = tenantHandler(cfg.TenantHeader, handler)
+	}
 	return handler
 }
+
+// withMetricAttributes는 attrs를 otelhttp 레이블러에 더해 이 경로의 HTTP 서버 메트릭
+// (http.server.duration 등)에 붙입니다. 스팬에는 붙지 않습니다.
+// 등록할 때 정한 고정 값만 받으므로 경로마다 값이 하나씩 늘 뿐, 요청 값으로
+// 카디널리티가 커지지 않습니다. 요청에서 읽은 값을 여기에 넘기지 마세요.
+// otelhttp 핸들러 안쪽에 있어야 합니다.
+func withMetricAttributes(attrs []attribute.KeyValue, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		labeler, _ := otelhttp.LabelerFromContext(r.Context())
+		labeler.Add(attrs...)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newPrometheusHandler는 promhttp.Handler와 같지만 OpenMetrics 형식 협상을 켭니다.
+// Accept 헤더로 OpenMetrics를 요청한 스크레이프(Prometheus의 exemplar 저장 기능 등)에는
+// 히스토그램 버킷마다 그 구간에 기록된 요청의 trace_id, span_id 예시가 함께 나갑니다.
+// 예시는 샘플링된 스팬 안에서 기록된 측정값에만 붙습니다(SDK 기본 trace_based 필터).
+func newPrometheusHandler() http.Handler {
+	return promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}))
+}
+
+// newMetricsHandler는 별도 메트릭 서버용 핸들러를 만듭니다.
+// 스크레이프 트래픽이 추적을 어지럽히지 않도록 계측하지 않습니다.
+func newMetricsHandler(cfg config) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", newPrometheusHandler())
+	return authHandler(cfg, mux)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestMeterProviderReaders(t *testing.T) {
+	// 하나의 제공자에 붙은 주기적 리더와 Prometheus 리더가 같은 계측기를 봅니다.
+	tests := []struct {
+		name        string
+		prometheus  bool
+		wantPeriod  bool
+		wantScraped bool
+	}{
+		{"both readers", true, true, true},
+		{"prometheus disabled", false, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			exp, err := stdoutmetric.New(stdoutmetric.WithWriter(&out))
+			if err != nil {
+				t.Fatal(err)
+			}
+			reg := prometheus.NewRegistry()
+			cfg := defaultConfig()
+			cfg.Prometheus = tt.prometheus
+			cfg.PrometheusRegisterer = reg
+			mp, err := newMeterProvider(cfg, exp, resource.Empty())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mp.Shutdown(context.Background())
+
+			counter, err := mp.Meter("test").Int64Counter("readers.hits")
+			if err != nil {
+				t.Fatal(err)
+			}
+			counter.Add(context.Background(), 2)
+			if err := mp.ForceFlush(context.Background()); err != nil {
+				t.Fatalf("ForceFlush: %v", err)
+			}
+
+			if got := strings.Contains(out.String(), `"readers.hits"`); got != tt.wantPeriod {
+				t.Errorf("주기적 리더 출력에 readers.hits 있음 = %v, want %v", got, tt.wantPeriod)
+			}
+			got, ok := gatheredValue(t, reg, "dice_game_readers_hits_total")
+			if ok != tt.wantScraped || (ok && got != 2) {
+				t.Errorf("dice_game_readers_hits_total = %v (있음 %v), want 있음 %v", got, ok, tt.wantScraped)
+			}
+		})
+	}
+}
+
+func TestSetGlobalMeterProviderTwice(t *testing.T) {
+	// TestMain이 이미 한 번 설정했으므로 이번 호출은 두 번째입니다.
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	second := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(second))
+	defer mp.Shutdown(context.Background())
+	setGlobalMeterProvider(mp)
+	log.SetOutput(prev)
+	defer otel.SetMeterProvider(testTelemetry.meterProvider)
+
+	if !strings.Contains(buf.String(), "경고: 전역 MeterProvider를 다시 설정합니다") {
+		t.Errorf("경고가 없습니다: %q", buf.String())
+	}
+
+	// init에서 만든 계측기는 여전히 처음 설정된 제공자에 기록됩니다.
+	before := counterValue(t, "http.server.not_found")
+	serve(newTestHandler(defaultConfig()), httptest.NewRequest(http.MethodGet, "/no/such/route", nil))
+	if got := counterValue(t, "http.server.not_found") - before; got != 1 {
+		t.Errorf("처음 제공자의 http.server.not_found 증가량 = %d, want 1", got)
+	}
+	var rm metricdata.ResourceMetrics
+	if err := second.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := findMetric(rm, "http.server.not_found"); ok {
+		t.Error("두 번째 제공자에도 기록되었습니다")
+	}
+}
+
+// captureMetricExporter는 내보낸 메트릭 이름별 집계 데이터를 기록합니다.
+type captureMetricExporter struct {
+	sdkmetric.Exporter
+	got map[string]metricdata.Aggregation
+}
+
+func (e *captureMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			e.got[m.Name] = m.Data
+		}
+	}
+	return nil
+}
+
+func TestMeterProviderDropBuckets(t *testing.T) {
+	// 버킷을 빼는 설정은 주기적 리더의 집계만 바꾸고, 같은 제공자의 Prometheus 리더는 버킷을 유지합니다.
+	stdout, err := stdoutmetric.New(stdoutmetric.WithWriter(io.Discard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := &captureMetricExporter{Exporter: stdout, got: map[string]metricdata.Aggregation{}}
+	reg := prometheus.NewRegistry()
+	cfg := defaultConfig()
+	cfg.MetricsDropBuckets = true
+	cfg.PrometheusRegisterer = reg
+	mp, err := newMeterProvider(cfg, exp, resource.Empty())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mp.Shutdown(context.Background())
+
+	// 계측기가 권고한 경계도 리더의 집계 선택에 밀려 버킷이 남지 않아야 합니다.
+	hist, err := mp.Meter("test").Float64Histogram("drop.latency", metric.WithExplicitBucketBoundaries(1, 2, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []float64{0.5, 3, 7} {
+		hist.Record(context.Background(), v)
+	}
+	if err := mp.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+
+	data, ok := exp.got["drop.latency"].(metricdata.ExponentialHistogram[float64])
+	if !ok {
+		t.Fatalf("drop.latency 집계 = %T, want 지수 히스토그램", exp.got["drop.latency"])
+	}
+	dp := data.DataPoints[0]
+	if dp.Count != 3 || dp.Sum != 10.5 || len(dp.PositiveBucket.Counts) > 2 {
+		t.Errorf("데이터 포인트 개수 %d, 합계 %v, 버킷 %v, want 3, 10.5, 버킷 두 개 이하", dp.Count, dp.Sum, dp.PositiveBucket.Counts)
+	}
+	if max, _ := dp.Max.Value(); max != 7 {
+		t.Errorf("최댓값 = %v, want 7", max)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buckets int
+	for _, f := range families {
+		if f.GetName() == "dice_game_drop_latency" {
+			buckets = len(f.GetMetric()[0].GetHistogram().GetBucket())
+		}
+	}
+	if buckets != 3 {
+		t.Errorf("Prometheus 버킷 수 = %d, want 3", buckets)
+	}
+}
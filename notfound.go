@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var notFoundCnt metric.Int64Counter
+
+func init() {
+	var err error
+	notFoundCnt, err = meter.Int64Counter("http.server.not_found",
+		metric.WithDescription("등록되지 않은 경로로 들어온 요청 수"),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// problem은 RFC 9457 problem details 응답 본문입니다.
+type problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// notFound는 어떤 경로와도 일치하지 않은 요청에 problem+json 404를 응답합니다.
+func notFound(w http.ResponseWriter, r *http.Request) {
+	notFoundCnt.Add(r.Context(), 1,
+		metric.WithAttributes(attribute.String("http.request.method", r.Method)))
+
+	writeProblem(w, r, http.StatusNotFound, "요청한 경로를 찾을 수 없습니다")
+}
+
+// routeProbe는 notFoundOrNotAllowed가 경로 일치만 확인하려고 등록하는 빈 핸들러입니다.
+type routeProbe struct{}
+
+func (routeProbe) ServeHTTP(http.ResponseWriter, *http.Request) {}
+
+// probeMethods는 405 응답의 Allow 헤더를 만들 때 확인하는 메서드입니다.
+var probeMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions,
+}
+
+// notFoundOrNotAllowed는 "/" 패턴용 핸들러입니다. "/"가 모든 메서드와 일치하므로
+// ServeMux의 405 처리를 대신합니다. routes에 등록된 패턴 중 다른 메서드로 이 경로와
+// 일치하는 것이 있으면 Allow 헤더와 함께 405를, 없으면 notFound로 404를 응답합니다.
+// 405는 http.server.not_found에 세지 않습니다.
+func notFoundOrNotAllowed(routes *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var allow []string
+		for _, m := range probeMethods {
+			probe := r.Clone(r.Context())
+			probe.Method = m
+			// 슬래시 리다이렉트 같은 ServeMux 자체 핸들러는 일치로 보지 않습니다.
+			if h, _ := routes.Handler(probe); h == (routeProbe{}) {
+				allow = append(allow, m)
+			}
+		}
+		if len(allow) == 0 {
+			notFound(w, r)
+			return
+		}
+		w.Header().Set("Allow", strings.Join(allow, ", "))
+		writeProblem(w, r, http.StatusMethodNotAllowed, "이 경로에서 지원하지 않는 메서드입니다")
+	})
+}
+
+// writeProblem은 status와 detail로 problem+json 응답을 씁니다.
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	})
+	if err != nil {
+		logger.ErrorContext(r.Context(), "응답 쓰기 실패", "error", err)
+	}
+}
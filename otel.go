@@ -3,24 +3,42 @@ package main
 import (
 	"context"
 	"errors"
-	"go.opentelemetry.io/otel/exporters/prometheus"
 	llog "log"
-	"time"
+	"log/slog"
+	"os"
+	"sync/atomic"
 
+	promclient "github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
-	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
-	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/log/global"
+	otelmetric "go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 )
 
+// setupDuration은 setupOTelSDK가 걸린 시간입니다. 시작할 때 한 번만 기록하며,
+// 배포마다 텔레메트리 초기화 비용(예: OTLP 연결 확인)을 비교하는 데 씁니다.
+var setupDuration otelmetric.Float64Gauge
+
+func init() {
+	var err error
+	setupDuration, err = meter.Float64Gauge("otel.sdk.setup.duration",
+		otelmetric.WithDescription("OpenTelemetry SDK 설정에 걸린 시간"),
+		otelmetric.WithUnit("s"))
+	if err != nil {
+		panic(err)
+	}
+}
+
 // setupOTelSDK는 OpenTelemetry 파이프라인을 부트스트랩합니다.
 // 에러가 반환되지 않으면, 적절한 정리를 위해 shutdown을 호출하세요.
-func setupOTelSDK(ctx context.Context) (shutdown func(context.Context) error, err error) {
+// OTLP 내보내기가 준비 조건으로 설정되면 ready에 연결 검사를 등록합니다.
+// 테스트에서 네트워크 없이 부르려면 cfg.Offline을 켭니다.
+func setupOTelSDK(ctx context.Context, cfg config, ready *readiness) (shutdown func(context.Context) error, err error) {
 	var shutdownFuncs []func(context.Context) error
 
 	// shutdown은 shutdownFuncs를 통해 등록된 정리 함수들을 호출합니다.
@@ -40,12 +58,70 @@ func setupOTelSDK(ctx context.Context) (shutdown func(context.Context) error, er
 		err = errors.Join(inErr, shutdown(ctx))
 	}
 
+	if cfg.Demo {
+		llog.Printf("데모 모드: 스팬 배치 간격 %v, 메트릭 내보내기 간격 %v", cfg.traceBatchTimeout(), cfg.metricInterval())
+	} else {
+		llog.Printf("운영 모드: 스팬 배치 간격 %v, 메트릭 내보내기 간격 %v(SDK 기본값)", cfg.traceBatchTimeout(), cfg.metricInterval())
+	}
+
+	// /debug/otel에 보일 익스포터 이름과 SDK 에러를 기록합니다.
+	telemetryStatus.setExporters(cfg)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		telemetryStatus.sdk.set(err)
+		llog.Printf("OpenTelemetry 에러: %v", err)
+	}))
+
 	// Propagator 설정
-	prop := newPropagator()
+	prop := newPropagator(cfg)
 	otel.SetTextMapPropagator(prop)
 
+	// OTLP를 쓰면 세 신호의 익스포터가 하나의 gRPC 연결을 공유합니다.
+	// 경로 접두사가 있는 엔드포인트는 OTLP/HTTP로 보내므로 연결이 없습니다.
+	conn, err := newOTLPConn(cfg)
+	if err != nil {
+		handleErr(err)
+		return
+	}
+	if conn != nil && cfg.ReadyRequiresOTLP {
+		ready.addCheck("otlp", otlpConnCheck(conn))
+	}
+	if conn != nil {
+		// 연결은 모든 익스포터가 종료된 뒤에 닫혀야 하므로 마지막 정리 함수로 등록합니다.
+		// 설정 도중 실패하면 여기서 바로 닫습니다.
+		defer func() {
+			if err != nil {
+				err = errors.Join(err, conn.Close())
+				return
+			}
+			shutdownFuncs = append(shutdownFuncs, func(context.Context) error {
+				return conn.Close()
+			})
+		}()
+	}
+
+	res, err := newResource(ctx, cfg)
+	if err != nil {
+		handleErr(err)
+		return
+	}
+	var traceOpts []trace.TracerProviderOption
+	var meterOpts []metric.Option
+
+	// SIGQUIT 덤프가 켜져 있으면 진행 중인 스팬과 메트릭 스냅샷을 수집합니다.
+	if cfg.SigquitDump {
+		dumper := newTelemetryDumper(cfg.DumpDir)
+		traceOpts = append(traceOpts, trace.WithSpanProcessor(dumper.spans))
+		meterOpts = append(meterOpts, metric.WithReader(dumper.metrics))
+		shutdownFuncs = append(shutdownFuncs, dumper.watch())
+	}
+
 	// 추적 제공자 설정
-	tracerProvider, err := newTraceProvider()
+	traceExporter, err := newTraceExporter(ctx, cfg, conn)
+	if err != nil {
+		handleErr(err)
+		return
+	}
+	tracerProvider, err := newTraceProvider(ctx, cfg, res, traceExporter, traceOpts...)
 	if err != nil {
 		handleErr(err)
 		return
@@ -54,105 +130,299 @@ func setupOTelSDK(ctx context.Context) (shutdown func(context.Context) error, er
 	otel.SetTracerProvider(tracerProvider)
 
 	// 측정 제공자 설정
-	meterProvider, err := newMeterProvider()
+	metricExporter, err := newMetricExporter(ctx, cfg, conn)
 	if err != nil {
 		handleErr(err)
 		return
 	}
-	shutdownFuncs = append(shutdownFuncs, meterProvider.Shutdown)
-	otel.SetMeterProvider(meterProvider)
-
-	promMeterProvider, err := newPrometheusMeterProvider()
+	meterProvider, err := newMeterProvider(cfg, metricExporter, res, meterOpts...)
 	if err != nil {
 		handleErr(err)
 		return
 	}
-	// Prometheus provider 도 전역 provider 로 설정
-	shutdownFuncs = append(shutdownFuncs, promMeterProvider.Shutdown) // 없어야하나? 있어야하나?
-	otel.SetMeterProvider(promMeterProvider)
+	shutdownFuncs = append(shutdownFuncs, meterProvider.Shutdown)
+	setGlobalMeterProvider(meterProvider)
+	if cfg.RuntimeMetrics {
+		var unregister func(context.Context) error
+		unregister, err = registerRuntimeMetrics(cfg.RuntimeMetricsInterval)
+		if err != nil {
+			handleErr(err)
+			return
+		}
+		// 제공자보다 먼저 해제되도록 앞에 둡니다.
+		shutdownFuncs = append([]func(context.Context) error{unregister}, shutdownFuncs...)
+	}
 
 	// 로거 제공자 설정
-	loggerProvider, err := newLoggerProvider()
+	logExporters, err := newLogExporters(ctx, cfg, conn)
 	if err != nil {
 		handleErr(err)
 		return
 	}
-	shutdownFuncs = append(shutdownFuncs, loggerProvider.Shutdown)
+	loggerProvider := newLoggerProvider(cfg, logExporters, res)
+	// 종료 시간 로그는 추적과 측정 제공자가 종료된 뒤, 로거 제공자가 종료되기 전에 남깁니다.
+	shutdownFuncs = append(shutdownFuncs, logShutdownDuration, loggerProvider.Shutdown)
 	global.SetLoggerProvider(loggerProvider)
 
+	// slog.InfoContext 같은 패키지 함수도 같은 브리지로 보내 요청 컨텍스트의 추적 ID가 붙게 합니다.
+	// slog.SetDefault는 표준 log 패키지 출력까지 slog로 돌리므로, 시작과 종료 메시지는
+	// 지금처럼 stderr에 남도록 표준 로거를 되돌립니다.
+	slog.SetDefault(logger)
+	llog.SetOutput(os.Stderr)
+	llog.SetFlags(llog.LstdFlags)
+
 	return
 }
 
-func newPropagator() propagation.TextMapPropagator {
-	return propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{},
-		propagation.Baggage{},
-	)
-}
+// globalMeterProviderSet은 setGlobalMeterProvider가 이미 호출되었는지를 기록합니다.
+var globalMeterProviderSet atomic.Bool
 
-func newTraceProvider() (*trace.TracerProvider, error) {
-	traceExporter, err := stdouttrace.New(
-		stdouttrace.WithPrettyPrint())
-	if err != nil {
-		return nil, err
+// setGlobalMeterProvider는 otel.SetMeterProvider를 호출하되, 두 번째 호출부터 경고를 남깁니다.
+//
+// 패키지 init 등에서 setupOTelSDK보다 먼저 otel.Meter로 만든 계측기는 버려지지 않고,
+// 전역 제공자가 처음 설정될 때 그 제공자에 한 번만 연결됩니다. 따라서 전역 제공자를
+// 다시 설정하면 이런 계측기의 값은 새 제공자로 가지 않고 조용히 사라진 것처럼 보입니다.
+// 권장하는 방식은 전역 제공자를 한 번만 설정하고, 여러 내보내기 대상이 필요하면
+// 하나의 제공자에 리더를 여러 개 붙이는 것입니다.
+func setGlobalMeterProvider(mp *metric.MeterProvider) {
+	if globalMeterProviderSet.Swap(true) {
+		llog.Printf("경고: 전역 MeterProvider를 다시 설정합니다. " +
+			"먼저 만든 계측기는 처음 설정된 제공자에만 기록됩니다")
 	}
+	otel.SetMeterProvider(mp)
+}
 
-	traceProvider := trace.NewTracerProvider(
-		trace.WithBatcher(traceExporter,
-			// 기본값은 5초입니다. 시연을 위해 1초로 설정했습니다.
-			trace.WithBatchTimeout(time.Second)),
-	)
-	return traceProvider, nil
+// newPropagator는 TraceContext 전파기를, 배기지 전파가 켜져 있으면 Baggage 전파기도 함께 씁니다.
+// Baggage를 빼면 나가는 요청에 baggage 헤더를 싣지 않고 들어오는 헤더도 읽지 않으므로
+// 내부 컨텍스트가 외부 호출 대상에 새지 않습니다.
+//
+// Datadog 전파가 켜져 있으면 Datadog 헤더를 TraceContext보다 먼저 읽으므로,
+// 두 헤더가 모두 있으면 traceparent가 이깁니다.
+func newPropagator(cfg config) propagation.TextMapPropagator {
+	var props []propagation.TextMapPropagator
+	if cfg.DatadogPropagation {
+		props = append(props, datadogPropagator{})
+	}
+	props = append(props, propagation.TraceContext{})
+	if cfg.BaggagePropagation {
+		props = append(props, propagation.Baggage{})
+	}
+	return propagation.NewCompositeTextMapPropagator(props...)
 }
 
-func newMeterProvider() (*metric.MeterProvider, error) {
-	metricExporter, err := stdoutmetric.New()
-	if err != nil {
-		return nil, err
+// newTraceProvider는 traceExporter로 내보내는 추적 제공자를 만듭니다.
+// 제공자가 종료될 때 traceExporter도 함께 종료됩니다.
+// res는 제공자와, 켜져 있으면 내보내기 스팬용 제공자에도 붙습니다.
+func newTraceProvider(ctx context.Context, cfg config, res *resource.Resource, traceExporter trace.SpanExporter, opts ...trace.TracerProviderOption) (*trace.TracerProvider, error) {
+	// 디스크 버퍼보다 안쪽에서 세야 버퍼가 감춘 실패도 기록됩니다.
+	traceExporter = statusSpanExporter{traceExporter, &telemetryStatus.traces}
+	if cfg.SDKMetrics {
+		traceExporter = countingSpanExporter{traceExporter}
+	}
+	if cfg.TraceBufferDir != "" {
+		buffered, err := newDiskBufferExporter(traceExporter, cfg.TraceBufferDir, cfg.TraceBufferMaxBytes)
+		if err != nil {
+			return nil, errors.Join(err, traceExporter.Shutdown(ctx))
+		}
+		traceExporter = buffered
+	}
+	if cfg.DebugExportSpans {
+		traceExporter = newTracedSpanExporter(traceExporter, res)
 	}
 
-	meterProvider := metric.NewMeterProvider(
-		metric.WithReader(metric.NewPeriodicReader(metricExporter,
-			// 기본값은 1분입니다. 시연을 위해 3초로 설정했습니다.
-			metric.WithInterval(3*time.Second))),
-	)
-	return meterProvider, nil
+	sampler, err := newSampler(cfg)
+	if err != nil {
+		return nil, errors.Join(err, traceExporter.Shutdown(ctx))
+	}
+	sampler = newDebugSampler(sampler, cfg.TrustInboundDebug)
+	if len(cfg.SkipUserAgents) > 0 {
+		sampler = suppressingSampler{sampler}
+	}
+	processor := newSpanProcessor(cfg, traceExporter)
+	if cfg.ClampSpanDurations {
+		processor = newDurationClampProcessor(processor)
+	}
+	processor = newAttributeTruncateProcessor(processor, cfg.AttributeValueMaxLength)
+	// 제공자를 만들기 전에 실패하면 여기까지 만든 프로세서를 직접 종료해야 합니다.
+	// recentSpans처럼 함께 쓰는 전역 프로세서는 넣지 않습니다.
+	owned := []trace.SpanProcessor{processor}
+	shutdownProcessors := func(err error) error {
+		for _, p := range owned {
+			err = errors.Join(err, p.Shutdown(ctx))
+		}
+		return err
+	}
+	providerOpts := []trace.TracerProviderOption{
+		trace.WithResource(res),
+		trace.WithSampler(sampler),
+		trace.WithSpanProcessor(newAttributeDropProcessor(processor, cfg.SpanDropAttributes)),
+	}
+	if len(cfg.FeatureFlagAttributes) > 0 {
+		providerOpts = append(providerOpts, trace.WithSpanProcessor(newFeatureFlagProcessor(cfg.FeatureFlagAttributes)))
+	}
+	if cfg.AdminTraces {
+		providerOpts = append(providerOpts, trace.WithSpanProcessor(recentSpans))
+	}
+	if cfg.SDKMetrics {
+		providerOpts = append(providerOpts, trace.WithSpanProcessor(liveSpanProcessor{}))
+	}
+	if len(cfg.BaggageSpanAttributes) > 0 {
+		providerOpts = append(providerOpts, trace.WithSpanProcessor(newBaggageSpanProcessor(cfg.BaggageSpanAttributes)))
+	}
+	if cfg.TenantAttributesFile != "" {
+		tenants, err := newTenantProcessor(cfg.TenantAttributesFile)
+		if err != nil {
+			return nil, shutdownProcessors(err)
+		}
+		owned = append(owned, tenants)
+		providerOpts = append(providerOpts, trace.WithSpanProcessor(tenants))
+	}
+	if cfg.DynamicAttributesFile != "" {
+		dynamic, err := newDynamicAttributeProcessor(cfg.DynamicAttributesFile, cfg.DynamicAttributesInterval)
+		if err != nil {
+			return nil, shutdownProcessors(err)
+		}
+		owned = append(owned, dynamic)
+		providerOpts = append(providerOpts, trace.WithSpanProcessor(dynamic))
+	}
+	if cfg.IDSeed != 0 {
+		llog.Printf("시드 %d로 트레이스 ID를 만듭니다(테스트 전용)", cfg.IDSeed)
+		providerOpts = append(providerOpts, trace.WithIDGenerator(newSeededIDGenerator(cfg.IDSeed)))
+	}
+	// 메시지 큐로도 내보내면 기본 익스포터와 별도의 배치로 발행합니다.
+	if cfg.NATSURL != "" && !cfg.Offline {
+		pub, err := newNATSPublisher(cfg.NATSURL, cfg.NATSSubject)
+		if err != nil {
+			return nil, shutdownProcessors(err)
+		}
+		providerOpts = append(providerOpts, trace.WithBatcher(newPublishSpanExporter(pub),
+			trace.WithBatchTimeout(cfg.traceBatchTimeout())))
+	}
+	traceProvider := trace.NewTracerProvider(append(providerOpts, opts...)...)
+	return traceProvider, nil
 }
 
-func newLoggerProvider() (*log.LoggerProvider, error) {
-	logExporter, err := stdoutlog.New()
-	if err != nil {
-		return nil, err
+// newSpanProcessor는 설정에 따라 exporter로 내보낼 배치 또는 단순 프로세서를 만듭니다.
+func newSpanProcessor(cfg config, exporter trace.SpanExporter) trace.SpanProcessor {
+	if cfg.TracesProcessor == "simple" {
+		llog.Print("스팬을 끝날 때마다 바로 내보냅니다(simple 프로세서)")
+		return trace.NewSimpleSpanProcessor(exporter)
 	}
 
-	loggerProvider := log.NewLoggerProvider(
-		log.WithProcessor(log.NewBatchProcessor(logExporter)),
-	)
-	return loggerProvider, nil
+	// 배치 간격(WithBatchTimeout)은 얼마나 자주 내보낼지를, 내보내기 제한 시간
+	// (WithExportTimeout)은 한 번의 내보내기가 얼마나 걸려도 되는지를 정합니다.
+	// 수집기가 느리면 제한 시간을 줄여 내보내기가 쌓이지 않게 할 수 있습니다.
+	batchOpts := []trace.BatchSpanProcessorOption{
+		trace.WithBatchTimeout(cfg.traceBatchTimeout()),
+	}
+	exportTimeout := "SDK 기본값(30s)"
+	if cfg.TraceExportTimeout > 0 {
+		batchOpts = append(batchOpts, trace.WithExportTimeout(cfg.TraceExportTimeout))
+		exportTimeout = cfg.TraceExportTimeout.String()
+	}
+	llog.Printf("스팬 배치: 간격 %v, 내보내기 제한 시간 %s", cfg.traceBatchTimeout(), exportTimeout)
+	return trace.NewBatchSpanProcessor(exporter, batchOpts...)
 }
 
-func newPrometheusMeterProvider() (*metric.MeterProvider, error) {
-	exporter, err := prometheus.New(
-		prometheus.WithoutTargetInfo(),
-		prometheus.WithoutScopeInfo(),
-		// 디버깅 테스트용
-		prometheus.WithNamespace("dice_game"), // 네임스페이스 추가
-	)
-	if err != nil {
-		llog.Printf("Prometheus exporter creation failed: %v", err)
-		return nil, err
+// newMeterProvider는 전역으로 쓸 하나의 측정 제공자를 만듭니다.
+//
+// 제공자에는 metricExporter로 주기적으로 내보내는 리더와 /metrics가 수집하는 Prometheus
+// 리더가 함께 붙으므로, 모든 계측기가 두 곳에 같은 값으로 나타납니다. Prometheus 리더는
+// cfg.PrometheusRegisterer에 등록되며, nil이면 기본 레지스트리를 사용합니다. 다른
+// 애플리케이션에 포함될 때는 그 애플리케이션의 레지스트리를 넘기면 됩니다.
+// metricExporter가 nil이면 주기적 리더를, cfg.Prometheus가 꺼져 있으면 Prometheus 리더를 붙이지 않습니다.
+//
+// View(cfg.MetricViewsFile 포함)는 제공자 전체에 적용되므로 두 리더에 똑같이 적용됩니다.
+// 한쪽 리더에만 적용할 변환(버킷 제거, 백분위수)은 View 대신 익스포터를 감싸서 합니다.
+// opts로 리더를 더 붙일 수 있습니다. 모든 리더는 제공자의 Shutdown에서 함께 종료됩니다.
+func newMeterProvider(cfg config, metricExporter metric.Exporter, res *resource.Resource, opts ...metric.Option) (*metric.MeterProvider, error) {
+	// 제공자를 만들기 전에 실패하면 익스포터를 직접 종료합니다.
+	shutdownExporter := func() error {
+		if metricExporter == nil {
+			return nil
+		}
+		return metricExporter.Shutdown(context.Background())
+	}
+	var views []metric.View
+	if cfg.MetricViewsFile != "" {
+		var err error
+		views, err = loadViews(cfg.MetricViewsFile)
+		if err != nil {
+			return nil, errors.Join(err, shutdownExporter())
+		}
+		llog.Printf("%s에서 메트릭 View %d개를 읽었습니다", cfg.MetricViewsFile, len(views))
+	}
+	readers := []metric.Option{metric.WithResource(res)}
+	if cfg.Prometheus {
+		reg := cfg.PrometheusRegisterer
+		if reg == nil {
+			reg = promclient.DefaultRegisterer
+		}
+		promExporter, err := prometheus.New(
+			prometheus.WithRegisterer(reg),
+			prometheus.WithoutTargetInfo(),
+			prometheus.WithoutScopeInfo(),
+			// 디버깅 테스트용
+			prometheus.WithNamespace("dice_game"), // 네임스페이스 추가
+		)
+		if err != nil {
+			llog.Printf("Prometheus exporter creation failed: %v", err)
+			return nil, errors.Join(err, shutdownExporter())
+		}
+		readers = append(readers, metric.WithReader(promExporter))
 	}
 
-	meterProvider := metric.NewMeterProvider(
-		metric.WithReader(exporter),
-		metric.WithView(metric.NewView(
-			metric.Instrument{Name: "dice.rolls"},
-			metric.Stream{Name: "dice_game_rolls_total"},
-		)),
-	)
+	if metricExporter != nil {
+		metricExporter = statusMetricExporter{metricExporter, &telemetryStatus.metrics}
+		// 버킷을 빼면 백분위수를 추정할 수 없으므로 validate가 MetricsPercentiles와 함께 쓰는 설정을 거절합니다.
+		if cfg.MetricsDropBuckets {
+			metricExporter = bucketDropExporter{metricExporter}
+		}
+		if cfg.MetricsPercentiles {
+			metricExporter = percentileExporter{metricExporter}
+		}
+		readers = append(readers, metric.WithReader(metric.NewPeriodicReader(metricExporter,
+			metric.WithInterval(cfg.metricInterval()))))
+	}
+	opts = append(readers, opts...)
+	if cfg.Admin {
+		opts = append(opts, metric.WithReader(cardinalityReader))
+	}
+	if view, ok := combinedView(views, cfg.ExemplarReservoirSize); ok {
+		opts = append(opts, metric.WithView(view))
+	}
+	return metric.NewMeterProvider(opts...), nil
+}
 
-	// 초기화 후 메트릭이 제대로 등록되었는지 확인하기 위한 로그
-	llog.Printf("Prometheus meter provider initialized")
+// newLoggerProvider는 logExporters마다 프로세서를 둔 로거 제공자를 만듭니다.
+func newLoggerProvider(cfg config, logExporters []log.Exporter, res *resource.Resource) *log.LoggerProvider {
+	// 배치 프로세서는 레코드가 배치 크기만큼 쌓이거나 내보내기 간격이 지나면 내보냅니다.
+	// 0이면 SDK 기본값(512개, 1초)이나 OTEL_BLRP_* 환경 변수를 따릅니다.
+	var batchOpts []log.BatchProcessorOption
+	if cfg.LogBatchSize > 0 {
+		batchOpts = append(batchOpts, log.WithExportMaxBatchSize(cfg.LogBatchSize))
+	}
+	if cfg.LogExportInterval > 0 {
+		batchOpts = append(batchOpts, log.WithExportInterval(cfg.LogExportInterval))
+	}
 
-	return meterProvider, nil
+	// 내보내기 대상마다 배치 프로세서를 따로 두므로 한쪽이 느려도 다른 쪽은 영향을 받지 않습니다.
+	// 프로세서는 모두 LoggerProvider.Shutdown에서 함께 종료됩니다.
+	opts := []log.LoggerProviderOption{log.WithResource(res)}
+	for _, logExporter := range logExporters {
+		logExporter = statusLogExporter{logExporter, &telemetryStatus.logs}
+		if cfg.SDKMetrics {
+			logExporter = countingLogExporter{logExporter}
+		}
+		var processor log.Processor
+		if cfg.LogsProcessor == "simple" {
+			processor = log.NewSimpleProcessor(logExporter)
+		} else {
+			processor = log.NewBatchProcessor(logExporter, batchOpts...)
+		}
+		// 심각도별 표본 추출은 프로세서에 들어가기 전에 대상마다 적용합니다.
+		processor = severityTextProcessor{processor}
+		opts = append(opts, log.WithProcessor(newSeveritySamplingProcessor(processor, cfg.LogSampleRatios)))
+	}
+	return log.NewLoggerProvider(opts...)
 }
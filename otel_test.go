@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// restoreGlobals는 setupOTelSDK가 바꾼 전역 제공자와 전파기를 테스트가 끝나면 되돌립니다.
+func restoreGlobals(t *testing.T) {
+	t.Helper()
+	tp, lp, def := otel.GetTracerProvider(), global.GetLoggerProvider(), slog.Default()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(tp)
+		otel.SetMeterProvider(testTelemetry.meterProvider)
+		global.SetLoggerProvider(lp)
+		otel.SetTextMapPropagator(newPropagator(defaultConfig()))
+		slog.SetDefault(def)
+	})
+}
+
+func TestSetupOTelSDKOffline(t *testing.T) {
+	// 수집기가 없는 주소를 OTLP 대상으로 줘도 네트워크에 나가지 않고 바로 끝나야 합니다.
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://127.0.0.1:1")
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp")
+	t.Setenv("OTEL_METRICS_EXPORTER", "otlp")
+	t.Setenv("OTEL_LOGS_EXPORTER", "otlp")
+	t.Setenv("OTEL_SAMPLE_READY_REQUIRES_OTLP", "true")
+	cfg, err := loadConfig(nil)
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	cfg.Offline = true
+	cfg.PrometheusRegisterer = prometheus.NewRegistry()
+	restoreGlobals(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ready := &readiness{}
+	start := time.Now()
+	shutdown, err := setupOTelSDK(ctx, cfg, ready)
+	if err != nil {
+		t.Fatalf("setupOTelSDK: %v", err)
+	}
+	// 오프라인이면 OTLP 연결을 만들지 않으므로 준비 검사도 붙지 않습니다.
+	if len(ready.checks) != 0 {
+		t.Errorf("준비 검사 %d개가 등록되었습니다", len(ready.checks))
+	}
+
+	// 전역 제공자가 설정되어 스팬과 로그를 만들 수 있어야 합니다.
+	_, span := otel.Tracer("test").Start(ctx, "offline")
+	if !span.SpanContext().IsSampled() {
+		t.Error("스팬이 표본 추출되지 않았습니다")
+	}
+	span.End()
+	slog.InfoContext(ctx, "오프라인 설정 테스트")
+
+	if err := shutdown(ctx); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("설정과 종료에 %v 걸렸습니다", elapsed)
+	}
+}
+
+func TestSetupOTelSDKOnlineError(t *testing.T) {
+	// 비교를 위해: 오프라인이 아니면 잘못된 OTLP 엔드포인트가 설정 에러가 됩니다.
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "ftp://collector:4317")
+	cfg, err := loadConfig(nil)
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	cfg.PrometheusRegisterer = prometheus.NewRegistry()
+	restoreGlobals(t)
+
+	if _, err := setupOTelSDK(context.Background(), cfg, &readiness{}); err == nil || !strings.Contains(err.Error(), `지원하지 않는 스킴 "ftp"`) {
+		t.Errorf("setupOTelSDK 에러 = %v", err)
+	}
+	cfg.Offline = true
+	shutdown, err := setupOTelSDK(context.Background(), cfg, &readiness{})
+	if err != nil {
+		t.Fatalf("오프라인 setupOTelSDK: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+}
+
+// failingMeter는 히스토그램을 만들 때 실패하는 Meter입니다.
+type failingMeter struct {
+	noop.Meter
+}
+
+func (failingMeter) Int64Histogram(string, ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	return nil, errors.New("instrument limit reached")
+}
+
+func TestSetupTelemetryInstrumentError(t *testing.T) {
+	// SDK 설정 뒤 계측기 생성이 실패하면 이미 만든 제공자를 종료하고 에러를 돌려줍니다.
+	cfg := defaultConfig()
+	cfg.Offline = true
+	cfg.PrometheusRegisterer = prometheus.NewRegistry()
+	restoreGlobals(t)
+
+	inst, shutdown, err := setupTelemetry(context.Background(), cfg, &readiness{}, failingMeter{})
+	if err == nil || !strings.Contains(err.Error(), "주사위 계측기 생성: instrument limit reached") {
+		t.Fatalf("setupTelemetry 에러 = %v", err)
+	}
+	if inst != nil || shutdown != nil {
+		t.Error("실패했는데 계측기나 종료 함수를 돌려줬습니다")
+	}
+	// 종료된 제공자는 기록하지 않는 스팬만 만듭니다.
+	_, span := otel.Tracer("test").Start(context.Background(), "after failure")
+	defer span.End()
+	if span.IsRecording() {
+		t.Error("계측기 생성이 실패했는데 추적 제공자가 종료되지 않았습니다")
+	}
+}
+
+func TestSetupTelemetry(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Offline = true
+	cfg.PrometheusRegisterer = prometheus.NewRegistry()
+	restoreGlobals(t)
+
+	inst, shutdown, err := setupTelemetry(context.Background(), cfg, &readiness{}, noop.Meter{})
+	if err != nil {
+		t.Fatalf("setupTelemetry: %v", err)
+	}
+	if inst == nil {
+		t.Fatal("계측기가 없습니다")
+	}
+	_, span := otel.Tracer("test").Start(context.Background(), "running")
+	recording := span.IsRecording()
+	span.End()
+	if !recording {
+		t.Error("설정된 추적 제공자가 스팬을 기록하지 않습니다")
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// telemetryStatus는 신호별 내보내기 결과와 SDK가 보고한 마지막 에러입니다.
+// 익스포터를 감싸 기록하며 /debug/otel로 볼 수 있습니다.
+var telemetryStatus = &pipelineStatuses{}
+
+type pipelineStatuses struct {
+	traces, metrics, logs exportStatus
+
+	// sdk는 otel.Handle로 보고된 에러(예: 배치 프로세서나 계측기 에러)입니다.
+	sdk lastError
+}
+
+// exportStatus는 한 신호의 익스포터 이름과 내보내기 결과를 셉니다. 항목 수는 추적과 로그는
+// 레코드, 메트릭은 메트릭 스트림 수입니다. 실패한 배치의 항목은 failed로 셉니다. 디스크 버퍼를
+// 쓰면 실패한 배치가 나중에 다시 내보내질 수 있으므로 failed가 곧 잃은 수는 아닙니다.
+type exportStatus struct {
+	exporter atomic.Value // string
+	exported atomic.Int64
+	failed   atomic.Int64
+	lastErr  lastError
+}
+
+func (s *exportStatus) record(n int, err error) {
+	if err != nil {
+		s.failed.Add(int64(n))
+		s.lastErr.set(err)
+		return
+	}
+	s.exported.Add(int64(n))
+}
+
+// lastError는 마지막 에러와 그 시각을 담습니다.
+type lastError struct {
+	mu  sync.Mutex
+	msg string
+	at  time.Time
+}
+
+func (e *lastError) set(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.msg, e.at = err.Error(), time.Now()
+}
+
+func (e *lastError) get() *errorStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.msg == "" {
+		return nil
+	}
+	return &errorStatus{Message: e.msg, Time: e.at}
+}
+
+// setExporters는 설정에서 고른 신호별 익스포터 이름을 기록합니다.
+func (p *pipelineStatuses) setExporters(cfg config) {
+	p.traces.exporter.Store(cfg.tracesExporter())
+	p.metrics.exporter.Store(cfg.metricsExporter())
+	p.logs.exporter.Store(strings.Join(cfg.logsExporters(), ","))
+}
+
+type errorStatus struct {
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+type signalStatus struct {
+	Exporter  string       `json:"exporter"`
+	Exported  int64        `json:"exported"`
+	Failed    int64        `json:"failed"`
+	LastError *errorStatus `json:"last_error,omitempty"`
+}
+
+// otelStatusResponse는 /debug/otel의 응답 본문입니다.
+type otelStatusResponse struct {
+	Traces       signalStatus `json:"traces"`
+	Metrics      signalStatus `json:"metrics"`
+	Logs         signalStatus `json:"logs"`
+	SDKLastError *errorStatus `json:"sdk_last_error,omitempty"`
+}
+
+func (s *exportStatus) snapshot() signalStatus {
+	exporter, _ := s.exporter.Load().(string)
+	return signalStatus{
+		Exporter:  exporter,
+		Exported:  s.exported.Load(),
+		Failed:    s.failed.Load(),
+		LastError: s.lastErr.get(),
+	}
+}
+
+// serveOTelStatus는 신호별 익스포터, 내보낸 수와 실패한 수, 마지막 에러를 반환합니다.
+func serveOTelStatus(w http.ResponseWriter, _ *http.Request) {
+	resp := otelStatusResponse{
+		Traces:       telemetryStatus.traces.snapshot(),
+		Metrics:      telemetryStatus.metrics.snapshot(),
+		Logs:         telemetryStatus.logs.snapshot(),
+		SDKLastError: telemetryStatus.sdk.get(),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// statusSpanExporter, statusMetricExporter, statusLogExporter는 내보내기 결과를 status에
+// 기록합니다. 다른 래퍼가 감춘 실패도 기록되도록 가장 안쪽에 둡니다.
+type statusSpanExporter struct {
+	trace.SpanExporter
+	status *exportStatus
+}
+
+func (e statusSpanExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.status.record(len(spans), err)
+	return err
+}
+
+type statusMetricExporter struct {
+	metric.Exporter
+	status *exportStatus
+}
+
+func (e statusMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, rm)
+	n := 0
+	for _, sm := range rm.ScopeMetrics {
+		n += len(sm.Metrics)
+	}
+	e.status.record(n, err)
+	return err
+}
+
+type statusLogExporter struct {
+	log.Exporter
+	status *exportStatus
+}
+
+func (e statusLogExporter) Export(ctx context.Context, records []log.Record) error {
+	err := e.Exporter.Export(ctx, records)
+	e.status.record(len(records), err)
+	return err
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// newOTLPConn은 추적, 메트릭, 로그 OTLP 익스포터가 함께 쓸 gRPC 연결을 만듭니다.
+// OTLP 엔드포인트가 설정되지 않았거나 cfg.Offline이면 nil을 반환합니다.
+// gRPC를 쓰는 신호가 없으면(모두 OTLP/HTTP) 역시 nil을 반환합니다.
+// 연결은 모든 익스포터가 종료된 뒤에 닫아야 합니다.
+func newOTLPConn(cfg config) (*grpc.ClientConn, error) {
+	if cfg.OTLPEndpoint == "" || cfg.Offline {
+		return nil, nil
+	}
+	ep, err := parseOTLPEndpoint(cfg.OTLPEndpoint, cfg.OTLPInsecure)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.usesOTLPGRPC() {
+		return nil, nil
+	}
+
+	creds := credentials.NewTLS(otlpTLSConfig())
+	if ep.insecure {
+		creds = insecure.NewCredentials()
+	}
+	return grpc.NewClient(ep.host,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(payloadSizeHandler{}))
+}
+
+// otlpConnCheck는 공유 gRPC 연결이 수집기에 연결되어 있는지 확인하는 준비 상태 검사를 만듭니다.
+// 연결은 첫 내보내기 전까지 유휴 상태일 수 있으므로, 유휴 상태면 연결을 시작시키고 실패로 봅니다.
+func otlpConnCheck(conn *grpc.ClientConn) func() error {
+	return func() error {
+		switch state := conn.GetState(); state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Idle:
+			conn.Connect()
+			return fmt.Errorf("OTLP 연결 상태 %v", state)
+		default:
+			return fmt.Errorf("OTLP 연결 상태 %v", state)
+		}
+	}
+}
+
+// otlpEndpoint는 OTEL_EXPORTER_OTLP_ENDPOINT 값을 나눈 결과입니다.
+type otlpEndpoint struct {
+	// host는 "host:port" 형식의 주소입니다.
+	host     string
+	insecure bool
+	// path는 "/otlp" 같은 경로 접두사이며 끝에 "/"가 없습니다. 경로가 없으면 비어 있습니다.
+	path string
+}
+
+// signalPath는 OTLP/HTTP로 signal("traces", "metrics", "logs")을 보낼 요청 경로를 반환합니다.
+// 경로 접두사 뒤에 표준 경로 "/v1/<signal>"을 붙입니다.
+func (e otlpEndpoint) signalPath(signal string) string {
+	return e.path + "/v1/" + signal
+}
+
+// parseOTLPEndpoint는 OTEL_EXPORTER_OTLP_ENDPOINT 값을 주소와 경로 접두사로 나눕니다.
+// "http://host:4317"은 평문, "https://host:4317"은 TLS 연결이며,
+// 스킴이 없는 "host:4317"은 OTEL_EXPORTER_OTLP_INSECURE를 따릅니다.
+//
+// gRPC는 요청 경로가 서비스와 메서드 이름으로 고정되어 있어 "https://host/otlp" 같은
+// 경로 접두사를 붙일 수 없으므로, 경로가 있는 엔드포인트는 OTLP/HTTP로만 보냅니다.
+func parseOTLPEndpoint(endpoint string, insecureDefault bool) (otlpEndpoint, error) {
+	if !strings.Contains(endpoint, "://") {
+		return otlpEndpoint{host: endpoint, insecure: insecureDefault}, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return otlpEndpoint{}, fmt.Errorf("OTEL_EXPORTER_OTLP_ENDPOINT: %w", err)
+	}
+	ep := otlpEndpoint{host: u.Host, path: strings.TrimRight(u.Path, "/")}
+	switch u.Scheme {
+	case "http":
+		ep.insecure = true
+	case "https":
+	default:
+		return otlpEndpoint{}, fmt.Errorf("OTEL_EXPORTER_OTLP_ENDPOINT: 지원하지 않는 스킴 %q", u.Scheme)
+	}
+	return ep, nil
+}
+
+// otlpTLSConfig는 OTLP gRPC와 HTTP 익스포터가 TLS 연결에 쓸 설정입니다.
+func otlpTLSConfig() *tls.Config {
+	return &tls.Config{MinVersion: tls.VersionTLS12}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestParseOTLPEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint        string
+		insecureDefault bool
+		want            otlpEndpoint
+		wantErr         string
+	}{
+		{endpoint: "collector:4317", want: otlpEndpoint{host: "collector:4317"}},
+		{endpoint: "collector:4317", insecureDefault: true, want: otlpEndpoint{host: "collector:4317", insecure: true}},
+		{endpoint: "http://collector:4317", want: otlpEndpoint{host: "collector:4317", insecure: true}},
+		{endpoint: "https://collector:4317", insecureDefault: true, want: otlpEndpoint{host: "collector:4317"}},
+		{endpoint: "https://collector:4317/", want: otlpEndpoint{host: "collector:4317"}},
+		// 게이트웨이 경로 접두사는 버리지 않고 OTLP/HTTP 요청 경로 앞에 붙입니다.
+		{endpoint: "https://gateway.example.com/otlp", want: otlpEndpoint{host: "gateway.example.com", path: "/otlp"}},
+		{endpoint: "http://gateway:4318/otlp/v1/", want: otlpEndpoint{host: "gateway:4318", insecure: true, path: "/otlp/v1"}},
+		{endpoint: "ftp://collector:4317", wantErr: `지원하지 않는 스킴 "ftp"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			got, err := parseOTLPEndpoint(tt.endpoint, tt.insecureDefault)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("에러 = %v, want %q 포함", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("parseOTLPEndpoint = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOTLPPathPrefix(t *testing.T) {
+	// 경로 접두사가 있는 엔드포인트는 gRPC 연결 없이 신호마다 "<접두사>/v1/<신호>"로 보냅니다.
+	var (
+		mu    sync.Mutex
+		paths = map[string]string{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths[r.URL.Path] = r.Header.Get("Content-Type")
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	defer srv.Close()
+
+	cfg := defaultConfig()
+	cfg.OTLPEndpoint = srv.URL + "/otlp/"
+	conn, err := newOTLPConn(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conn != nil {
+		conn.Close()
+		t.Fatal("경로가 있는 엔드포인트로 gRPC 연결을 만들었습니다")
+	}
+
+	ctx := context.Background()
+	traceExp, err := newTraceExporter(ctx, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer traceExp.Shutdown(ctx)
+	if err := traceExp.ExportSpans(ctx, tracetest.SpanStubs{{Name: "gateway"}}.Snapshots()); err != nil {
+		t.Errorf("ExportSpans: %v", err)
+	}
+	metricExp, err := newMetricExporter(ctx, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer metricExp.Shutdown(ctx)
+	if err := metricExp.Export(ctx, &metricdata.ResourceMetrics{}); err != nil {
+		t.Errorf("메트릭 Export: %v", err)
+	}
+	logExps, err := newLogExporters(ctx, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range logExps {
+		defer e.Shutdown(ctx)
+		if err := e.Export(ctx, []sdklog.Record{{}}); err != nil {
+			t.Errorf("로그 Export: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, path := range []string{"/otlp/v1/traces", "/otlp/v1/metrics", "/otlp/v1/logs"} {
+		if ct, ok := paths[path]; !ok || ct != "application/x-protobuf" {
+			t.Errorf("%s 요청 Content-Type = %q (받음 %v), want application/x-protobuf", path, ct, ok)
+		}
+	}
+	if len(paths) != 3 {
+		t.Errorf("받은 경로 = %v", paths)
+	}
+}
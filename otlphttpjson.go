@@ -0,0 +1,428 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// 이 파일은 OTEL_EXPORTER_OTLP_PROTOCOL=http/json용 익스포터입니다. SDK의 OTLP/HTTP
+// 익스포터는 protobuf만 보내므로, SDK 데이터를 OTLP 메시지로 바꾼 뒤 marshalOTLPJSON으로
+// 직렬화해 "<접두사>/v1/<신호>"에 POST합니다. 재시도와 압축은 하지 않으며, 실패한 내보내기는
+// 에러로 돌려주어 배치 프로세서와 diskBufferExporter가 처리하게 합니다.
+
+// otlpJSONRequestTimeout은 요청 하나의 제한 시간으로, SDK OTLP 익스포터의 기본값과 같습니다.
+const otlpJSONRequestTimeout = 10 * time.Second
+
+// otlpJSONClient는 OTLP JSON 요청을 신호 하나의 URL로 보냅니다.
+type otlpJSONClient struct {
+	client *http.Client
+	url    string
+}
+
+func newOTLPJSONClient(ep otlpEndpoint, signal string) *otlpJSONClient {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	scheme := "http"
+	if !ep.insecure {
+		scheme = "https"
+		transport.TLSClientConfig = otlpTLSConfig()
+	}
+	return &otlpJSONClient{
+		client: &http.Client{Transport: transport, Timeout: otlpJSONRequestTimeout},
+		url:    scheme + "://" + ep.host + ep.signalPath(signal),
+	}
+}
+
+// post는 m을 OTLP JSON으로 보냅니다. 2xx가 아닌 응답은 본문 앞부분을 담은 에러가 됩니다.
+func (c *otlpJSONClient) post(ctx context.Context, m proto.Message) error {
+	body, err := marshalOTLPJSON(m)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("OTLP/HTTP JSON %s: %s: %s", c.url, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+func (c *otlpJSONClient) shutdown() {
+	c.client.CloseIdleConnections()
+}
+
+// otlpJSONSpanExporter는 스팬 배치를 ExportTraceServiceRequest JSON으로 보냅니다.
+type otlpJSONSpanExporter struct {
+	c *otlpJSONClient
+}
+
+func (e *otlpJSONSpanExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	return e.c.post(ctx, &coltracepb.ExportTraceServiceRequest{ResourceSpans: spansToProto(spans)})
+}
+
+func (e *otlpJSONSpanExporter) Shutdown(ctx context.Context) error {
+	e.c.shutdown()
+	return nil
+}
+
+// otlpJSONMetricExporter는 수집한 메트릭을 ExportMetricsServiceRequest JSON으로 보냅니다.
+// 시간성과 집계는 SDK 기본값(누적, 계측기 종류별 기본 집계)을 씁니다.
+type otlpJSONMetricExporter struct {
+	c *otlpJSONClient
+}
+
+func (e *otlpJSONMetricExporter) Temporality(k metric.InstrumentKind) metricdata.Temporality {
+	return metric.DefaultTemporalitySelector(k)
+}
+
+func (e *otlpJSONMetricExporter) Aggregation(k metric.InstrumentKind) metric.Aggregation {
+	return metric.DefaultAggregationSelector(k)
+}
+
+func (e *otlpJSONMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	return e.c.post(ctx, &colmetricpb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{resourceMetricsToProto(rm)},
+	})
+}
+
+func (e *otlpJSONMetricExporter) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+func (e *otlpJSONMetricExporter) Shutdown(ctx context.Context) error {
+	e.c.shutdown()
+	return nil
+}
+
+// otlpJSONLogExporter는 로그 레코드를 ExportLogsServiceRequest JSON으로 보냅니다.
+type otlpJSONLogExporter struct {
+	c *otlpJSONClient
+}
+
+func (e *otlpJSONLogExporter) Export(ctx context.Context, records []log.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return e.c.post(ctx, &collogspb.ExportLogsServiceRequest{ResourceLogs: logRecordsToProto(records)})
+}
+
+func (e *otlpJSONLogExporter) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+func (e *otlpJSONLogExporter) Shutdown(ctx context.Context) error {
+	e.c.shutdown()
+	return nil
+}
+
+func resourceMetricsToProto(rm *metricdata.ResourceMetrics) *metricspb.ResourceMetrics {
+	out := &metricspb.ResourceMetrics{
+		Resource:  resourceToProto(rm.Resource),
+		SchemaUrl: rm.Resource.SchemaURL(),
+	}
+	for _, sm := range rm.ScopeMetrics {
+		psm := &metricspb.ScopeMetrics{
+			Scope:     scopeToProto(sm.Scope),
+			SchemaUrl: sm.Scope.SchemaURL,
+		}
+		for _, m := range sm.Metrics {
+			pm := &metricspb.Metric{Name: m.Name, Description: m.Description, Unit: m.Unit}
+			switch data := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				pm.Data = &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{DataPoints: numberPointsToProto(data.DataPoints)}}
+			case metricdata.Gauge[float64]:
+				pm.Data = &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{DataPoints: numberPointsToProto(data.DataPoints)}}
+			case metricdata.Sum[int64]:
+				pm.Data = &metricspb.Metric_Sum{Sum: sumToProto(data)}
+			case metricdata.Sum[float64]:
+				pm.Data = &metricspb.Metric_Sum{Sum: sumToProto(data)}
+			case metricdata.Histogram[int64]:
+				pm.Data = &metricspb.Metric_Histogram{Histogram: histogramToProto(data)}
+			case metricdata.Histogram[float64]:
+				pm.Data = &metricspb.Metric_Histogram{Histogram: histogramToProto(data)}
+			case metricdata.ExponentialHistogram[int64]:
+				pm.Data = &metricspb.Metric_ExponentialHistogram{ExponentialHistogram: expHistogramToProto(data)}
+			case metricdata.ExponentialHistogram[float64]:
+				pm.Data = &metricspb.Metric_ExponentialHistogram{ExponentialHistogram: expHistogramToProto(data)}
+			case metricdata.Summary:
+				pm.Data = &metricspb.Metric_Summary{Summary: summaryToProto(data)}
+			default:
+				// 알 수 없는 집계는 값 없이 보내지 않고 건너뜁니다.
+				continue
+			}
+			psm.Metrics = append(psm.Metrics, pm)
+		}
+		out.ScopeMetrics = append(out.ScopeMetrics, psm)
+	}
+	return out
+}
+
+func scopeToProto(s instrumentation.Scope) *commonpb.InstrumentationScope {
+	return &commonpb.InstrumentationScope{Name: s.Name, Version: s.Version}
+}
+
+func temporalityToProto(t metricdata.Temporality) metricspb.AggregationTemporality {
+	switch t {
+	case metricdata.DeltaTemporality:
+		return metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA
+	case metricdata.CumulativeTemporality:
+		return metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+	default:
+		return metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_UNSPECIFIED
+	}
+}
+
+func sumToProto[N int64 | float64](s metricdata.Sum[N]) *metricspb.Sum {
+	return &metricspb.Sum{
+		DataPoints:             numberPointsToProto(s.DataPoints),
+		AggregationTemporality: temporalityToProto(s.Temporality),
+		IsMonotonic:            s.IsMonotonic,
+	}
+}
+
+func numberPointsToProto[N int64 | float64](dps []metricdata.DataPoint[N]) []*metricspb.NumberDataPoint {
+	out := make([]*metricspb.NumberDataPoint, 0, len(dps))
+	for _, dp := range dps {
+		pdp := &metricspb.NumberDataPoint{
+			Attributes:        attributesToProto(dp.Attributes.ToSlice()),
+			StartTimeUnixNano: timeToProto(dp.StartTime),
+			TimeUnixNano:      timeToProto(dp.Time),
+			Exemplars:         exemplarsToProto(dp.Exemplars),
+		}
+		switch v := any(dp.Value).(type) {
+		case int64:
+			pdp.Value = &metricspb.NumberDataPoint_AsInt{AsInt: v}
+		case float64:
+			pdp.Value = &metricspb.NumberDataPoint_AsDouble{AsDouble: v}
+		}
+		out = append(out, pdp)
+	}
+	return out
+}
+
+func histogramToProto[N int64 | float64](h metricdata.Histogram[N]) *metricspb.Histogram {
+	out := &metricspb.Histogram{AggregationTemporality: temporalityToProto(h.Temporality)}
+	for _, dp := range h.DataPoints {
+		sum := float64(dp.Sum)
+		out.DataPoints = append(out.DataPoints, &metricspb.HistogramDataPoint{
+			Attributes:        attributesToProto(dp.Attributes.ToSlice()),
+			StartTimeUnixNano: timeToProto(dp.StartTime),
+			TimeUnixNano:      timeToProto(dp.Time),
+			Count:             dp.Count,
+			Sum:               &sum,
+			BucketCounts:      dp.BucketCounts,
+			ExplicitBounds:    dp.Bounds,
+			Exemplars:         exemplarsToProto(dp.Exemplars),
+			Min:               extremaToProto(dp.Min),
+			Max:               extremaToProto(dp.Max),
+		})
+	}
+	return out
+}
+
+func expHistogramToProto[N int64 | float64](h metricdata.ExponentialHistogram[N]) *metricspb.ExponentialHistogram {
+	out := &metricspb.ExponentialHistogram{AggregationTemporality: temporalityToProto(h.Temporality)}
+	for _, dp := range h.DataPoints {
+		sum := float64(dp.Sum)
+		out.DataPoints = append(out.DataPoints, &metricspb.ExponentialHistogramDataPoint{
+			Attributes:        attributesToProto(dp.Attributes.ToSlice()),
+			StartTimeUnixNano: timeToProto(dp.StartTime),
+			TimeUnixNano:      timeToProto(dp.Time),
+			Count:             dp.Count,
+			Sum:               &sum,
+			Scale:             dp.Scale,
+			ZeroCount:         dp.ZeroCount,
+			Positive: &metricspb.ExponentialHistogramDataPoint_Buckets{
+				Offset: dp.PositiveBucket.Offset, BucketCounts: dp.PositiveBucket.Counts,
+			},
+			Negative: &metricspb.ExponentialHistogramDataPoint_Buckets{
+				Offset: dp.NegativeBucket.Offset, BucketCounts: dp.NegativeBucket.Counts,
+			},
+			Exemplars:     exemplarsToProto(dp.Exemplars),
+			Min:           extremaToProto(dp.Min),
+			Max:           extremaToProto(dp.Max),
+			ZeroThreshold: dp.ZeroThreshold,
+		})
+	}
+	return out
+}
+
+func summaryToProto(s metricdata.Summary) *metricspb.Summary {
+	out := &metricspb.Summary{}
+	for _, dp := range s.DataPoints {
+		pdp := &metricspb.SummaryDataPoint{
+			Attributes:        attributesToProto(dp.Attributes.ToSlice()),
+			StartTimeUnixNano: timeToProto(dp.StartTime),
+			TimeUnixNano:      timeToProto(dp.Time),
+			Count:             dp.Count,
+			Sum:               dp.Sum,
+		}
+		for _, q := range dp.QuantileValues {
+			pdp.QuantileValues = append(pdp.QuantileValues, &metricspb.SummaryDataPoint_ValueAtQuantile{Quantile: q.Quantile, Value: q.Value})
+		}
+		out.DataPoints = append(out.DataPoints, pdp)
+	}
+	return out
+}
+
+func extremaToProto[N int64 | float64](e metricdata.Extrema[N]) *float64 {
+	v, ok := e.Value()
+	if !ok {
+		return nil
+	}
+	f := float64(v)
+	return &f
+}
+
+func exemplarsToProto[N int64 | float64](exemplars []metricdata.Exemplar[N]) []*metricspb.Exemplar {
+	if len(exemplars) == 0 {
+		return nil
+	}
+	out := make([]*metricspb.Exemplar, 0, len(exemplars))
+	for _, ex := range exemplars {
+		pex := &metricspb.Exemplar{
+			FilteredAttributes: attributesToProto(ex.FilteredAttributes),
+			TimeUnixNano:       timeToProto(ex.Time),
+			SpanId:             ex.SpanID,
+			TraceId:            ex.TraceID,
+		}
+		switch v := any(ex.Value).(type) {
+		case int64:
+			pex.Value = &metricspb.Exemplar_AsInt{AsInt: v}
+		case float64:
+			pex.Value = &metricspb.Exemplar_AsDouble{AsDouble: v}
+		}
+		out = append(out, pex)
+	}
+	return out
+}
+
+// timeToProto는 시각을 OTLP의 유닉스 나노초로 바꿉니다. 0 시각은 "없음"을 뜻하는 0이 됩니다.
+func timeToProto(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
+
+// logRecordsToProto는 로그 레코드를 리소스와 계측 범위별로 묶어 OTLP 메시지로 변환합니다.
+func logRecordsToProto(records []log.Record) []*logspb.ResourceLogs {
+	type scopeKey struct {
+		res   attribute.Distinct
+		scope instrumentation.Scope
+	}
+	var (
+		rlByRes   = map[attribute.Distinct]*logspb.ResourceLogs{}
+		slByScope = map[scopeKey]*logspb.ScopeLogs{}
+		out       []*logspb.ResourceLogs
+	)
+	for i := range records {
+		r := &records[i]
+		res := r.Resource()
+		resKey := res.Equivalent()
+		rl, ok := rlByRes[resKey]
+		if !ok {
+			rl = &logspb.ResourceLogs{
+				Resource:  resourceToProto(&res),
+				SchemaUrl: res.SchemaURL(),
+			}
+			rlByRes[resKey] = rl
+			out = append(out, rl)
+		}
+
+		key := scopeKey{res: resKey, scope: r.InstrumentationScope()}
+		sl, ok := slByScope[key]
+		if !ok {
+			sl = &logspb.ScopeLogs{Scope: scopeToProto(key.scope), SchemaUrl: key.scope.SchemaURL}
+			slByScope[key] = sl
+			rl.ScopeLogs = append(rl.ScopeLogs, sl)
+		}
+		sl.LogRecords = append(sl.LogRecords, logRecordToProto(r))
+	}
+	return out
+}
+
+func logRecordToProto(r *log.Record) *logspb.LogRecord {
+	pr := &logspb.LogRecord{
+		TimeUnixNano:           timeToProto(r.Timestamp()),
+		ObservedTimeUnixNano:   timeToProto(r.ObservedTimestamp()),
+		SeverityNumber:         logspb.SeverityNumber(r.Severity()),
+		SeverityText:           r.SeverityText(),
+		Body:                   logValueToProto(r.Body()),
+		DroppedAttributesCount: uint32(r.DroppedAttributes()),
+		Flags:                  uint32(r.TraceFlags()),
+	}
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		pr.Attributes = append(pr.Attributes, &commonpb.KeyValue{Key: kv.Key, Value: logValueToProto(kv.Value)})
+		return true
+	})
+	if tid := r.TraceID(); tid.IsValid() {
+		pr.TraceId = tid[:]
+	}
+	if sid := r.SpanID(); sid.IsValid() {
+		pr.SpanId = sid[:]
+	}
+	return pr
+}
+
+func logValueToProto(v otellog.Value) *commonpb.AnyValue {
+	switch v.Kind() {
+	case otellog.KindBool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v.AsBool()}}
+	case otellog.KindInt64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v.AsInt64()}}
+	case otellog.KindFloat64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v.AsFloat64()}}
+	case otellog.KindString:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.AsString()}}
+	case otellog.KindBytes:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: v.AsBytes()}}
+	case otellog.KindSlice:
+		return arrayValue(v.AsSlice(), logValueToProto)
+	case otellog.KindMap:
+		kvs := &commonpb.KeyValueList{}
+		for _, kv := range v.AsMap() {
+			kvs.Values = append(kvs.Values, &commonpb.KeyValue{Key: kv.Key, Value: logValueToProto(kv.Value)})
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{KvlistValue: kvs}}
+	default:
+		return nil
+	}
+}
+
+// 컴파일 시점에 인터페이스 구현을 확인합니다.
+var (
+	_ trace.SpanExporter = (*otlpJSONSpanExporter)(nil)
+	_ metric.Exporter    = (*otlpJSONMetricExporter)(nil)
+	_ log.Exporter       = (*otlpJSONLogExporter)(nil)
+)
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpJSONExporter는 스팬 배치를 OTLP JSON(ExportTraceServiceRequest) 형식으로
+// 한 줄씩 출력합니다. 콜렉터 없이도 jq 등으로 바로 처리할 수 있습니다.
+type otlpJSONExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newOTLPJSONExporter(w io.Writer) *otlpJSONExporter {
+	return &otlpJSONExporter{w: w}
+}
+
+func (e *otlpJSONExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	b, err := marshalOTLPJSON(&coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: spansToProto(spans),
+	})
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err = e.w.Write(append(b, '\n'))
+	return err
+}
+
+func (e *otlpJSONExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// marshalOTLPJSON은 OTLP JSON 인코딩 규칙에 맞게 메시지를 직렬화합니다.
+// OTLP JSON은 kind, status.code 같은 열거형을 이름이 아닌 정수로 쓰므로 UseEnumNumbers를 켭니다.
+// protojson은 bytes 필드를 base64로 쓰지만 OTLP JSON은 traceId/spanId를
+// 16진수 문자열로 요구하므로 해당 필드만 다시 인코딩합니다.
+func marshalOTLPJSON(m proto.Message) ([]byte, error) {
+	b, err := protojson.MarshalOptions{UseEnumNumbers: true}.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if err := recodeIDs(v, func(s string) (string, error) {
+		raw, err := base64.StdEncoding.DecodeString(s)
+		return hex.EncodeToString(raw), err
+	}); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// recodeIDs는 JSON 트리에서 식별자 필드 값을 conv로 바꿉니다.
+func recodeIDs(v any, conv func(string) (string, error)) error {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if s, ok := child.(string); ok && isOTLPIDField(k) {
+				out, err := conv(s)
+				if err != nil {
+					return fmt.Errorf("%s: %w", k, err)
+				}
+				v[k] = out
+				continue
+			}
+			if err := recodeIDs(child, conv); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if err := recodeIDs(child, conv); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isOTLPIDField(k string) bool {
+	switch k {
+	case "traceId", "spanId", "parentSpanId":
+		return true
+	}
+	return false
+}
+
+// spansToProto는 SDK 스팬을 리소스와 계측 범위별로 묶어 OTLP 메시지로 변환합니다.
+func spansToProto(spans []trace.ReadOnlySpan) []*tracepb.ResourceSpans {
+	type scopeKey struct {
+		res   attribute.Distinct
+		scope instrumentation.Scope
+	}
+	var (
+		rsByRes   = map[attribute.Distinct]*tracepb.ResourceSpans{}
+		ssByScope = map[scopeKey]*tracepb.ScopeSpans{}
+		out       []*tracepb.ResourceSpans
+	)
+	for _, s := range spans {
+		res := s.Resource()
+		resKey := res.Equivalent()
+		rs, ok := rsByRes[resKey]
+		if !ok {
+			rs = &tracepb.ResourceSpans{
+				Resource:  resourceToProto(res),
+				SchemaUrl: res.SchemaURL(),
+			}
+			rsByRes[resKey] = rs
+			out = append(out, rs)
+		}
+
+		key := scopeKey{res: resKey, scope: s.InstrumentationScope()}
+		ss, ok := ssByScope[key]
+		if !ok {
+			ss = &tracepb.ScopeSpans{
+				Scope: &commonpb.InstrumentationScope{
+					Name:    key.scope.Name,
+					Version: key.scope.Version,
+				},
+				SchemaUrl: key.scope.SchemaURL,
+			}
+			ssByScope[key] = ss
+			rs.ScopeSpans = append(rs.ScopeSpans, ss)
+		}
+		ss.Spans = append(ss.Spans, spanToProto(s))
+	}
+	return out
+}
+
+func resourceToProto(res *resource.Resource) *resourcepb.Resource {
+	if res == nil {
+		return nil
+	}
+	return &resourcepb.Resource{Attributes: attributesToProto(res.Attributes())}
+}
+
+func spanToProto(s trace.ReadOnlySpan) *tracepb.Span {
+	sc := s.SpanContext()
+	tid, sid := sc.TraceID(), sc.SpanID()
+	ps := &tracepb.Span{
+		TraceId:                tid[:],
+		SpanId:                 sid[:],
+		TraceState:             sc.TraceState().String(),
+		Name:                   s.Name(),
+		Kind:                   tracepb.Span_SpanKind(s.SpanKind()),
+		StartTimeUnixNano:      uint64(s.StartTime().UnixNano()),
+		EndTimeUnixNano:        uint64(s.EndTime().UnixNano()),
+		Attributes:             attributesToProto(s.Attributes()),
+		DroppedAttributesCount: uint32(s.DroppedAttributes()),
+		DroppedEventsCount:     uint32(s.DroppedEvents()),
+		DroppedLinksCount:      uint32(s.DroppedLinks()),
+		Status:                 statusToProto(s.Status()),
+		Flags:                  uint32(sc.TraceFlags()),
+	}
+	if psid := s.Parent().SpanID(); psid.IsValid() {
+		ps.ParentSpanId = psid[:]
+	}
+	for _, ev := range s.Events() {
+		ps.Events = append(ps.Events, &tracepb.Span_Event{
+			Name:                   ev.Name,
+			TimeUnixNano:           uint64(ev.Time.UnixNano()),
+			Attributes:             attributesToProto(ev.Attributes),
+			DroppedAttributesCount: uint32(ev.DroppedAttributeCount),
+		})
+	}
+	for _, l := range s.Links() {
+		ltid, lsid := l.SpanContext.TraceID(), l.SpanContext.SpanID()
+		ps.Links = append(ps.Links, &tracepb.Span_Link{
+			TraceId:                ltid[:],
+			SpanId:                 lsid[:],
+			TraceState:             l.SpanContext.TraceState().String(),
+			Attributes:             attributesToProto(l.Attributes),
+			DroppedAttributesCount: uint32(l.DroppedAttributeCount),
+			Flags:                  uint32(l.SpanContext.TraceFlags()),
+		})
+	}
+	return ps
+}
+
+func statusToProto(st trace.Status) *tracepb.Status {
+	ps := &tracepb.Status{Message: st.Description}
+	switch st.Code {
+	case codes.Ok:
+		ps.Code = tracepb.Status_STATUS_CODE_OK
+	case codes.Error:
+		ps.Code = tracepb.Status_STATUS_CODE_ERROR
+	default:
+		ps.Code = tracepb.Status_STATUS_CODE_UNSET
+	}
+	return ps
+}
+
+func attributesToProto(attrs []attribute.KeyValue) []*commonpb.KeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make([]*commonpb.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		out = append(out, &commonpb.KeyValue{
+			Key:   string(kv.Key),
+			Value: attributeValueToProto(kv.Value),
+		})
+	}
+	return out
+}
+
+func attributeValueToProto(v attribute.Value) *commonpb.AnyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v.AsBool()}}
+	case attribute.INT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v.AsInt64()}}
+	case attribute.FLOAT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v.AsFloat64()}}
+	case attribute.STRING:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.AsString()}}
+	case attribute.BOOLSLICE:
+		return arrayValue(v.AsBoolSlice(), func(b bool) *commonpb.AnyValue {
+			return attributeValueToProto(attribute.BoolValue(b))
+		})
+	case attribute.INT64SLICE:
+		return arrayValue(v.AsInt64Slice(), func(i int64) *commonpb.AnyValue {
+			return attributeValueToProto(attribute.Int64Value(i))
+		})
+	case attribute.FLOAT64SLICE:
+		return arrayValue(v.AsFloat64Slice(), func(f float64) *commonpb.AnyValue {
+			return attributeValueToProto(attribute.Float64Value(f))
+		})
+	case attribute.STRINGSLICE:
+		return arrayValue(v.AsStringSlice(), func(s string) *commonpb.AnyValue {
+			return attributeValueToProto(attribute.StringValue(s))
+		})
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.Emit()}}
+	}
+}
+
+func arrayValue[T any](vs []T, conv func(T) *commonpb.AnyValue) *commonpb.AnyValue {
+	arr := &commonpb.ArrayValue{Values: make([]*commonpb.AnyValue, 0, len(vs))}
+	for _, v := range vs {
+		arr.Values = append(arr.Values, conv(v))
+	}
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: arr}}
+}
+
+// 컴파일 시점에 인터페이스 구현을 확인합니다.
+var _ trace.SpanExporter = (*otlpJSONExporter)(nil)
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMarshalOTLPJSON(t *testing.T) {
+	spans := tracetest.SpanStubs{{
+		Name: "roll",
+		SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{0xab, 1}, SpanID: trace.SpanID{0xcd, 2}, TraceFlags: trace.FlagsSampled,
+		}),
+		Parent:   trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{0xab, 1}, SpanID: trace.SpanID{0xef}}),
+		SpanKind: trace.SpanKindServer,
+		Status:   sdktrace.Status{Code: codes.Error, Description: "boom"},
+	}}
+	want := &coltracepb.ExportTraceServiceRequest{ResourceSpans: spansToProto(spans.Snapshots())}
+	b, err := marshalOTLPJSON(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+	span := v.(map[string]any)["resourceSpans"].([]any)[0].(map[string]any)["scopeSpans"].([]any)[0].(map[string]any)["spans"].([]any)[0].(map[string]any)
+	// OTLP JSON은 열거형을 이름("SPAN_KIND_SERVER")이 아닌 정수로 씁니다.
+	if kind, ok := span["kind"].(json.Number); !ok || kind.String() != "2" {
+		t.Errorf("kind = %#v, want 정수 2", span["kind"])
+	}
+	status := span["status"].(map[string]any)
+	if code, ok := status["code"].(json.Number); !ok || code.String() != "2" {
+		t.Errorf("status.code = %#v, want 정수 2", status["code"])
+	}
+	if span["traceId"] != "ab010000000000000000000000000000" || span["parentSpanId"] != "ef00000000000000" {
+		t.Errorf("식별자 = %v, %v, want 16진수", span["traceId"], span["parentSpanId"])
+	}
+
+	got, err := unmarshalOTLPJSON(v)
+	if err != nil {
+		t.Fatalf("unmarshalOTLPJSON: %v", err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("왕복 결과가 다릅니다\n got: %v\nwant: %v", got, want)
+	}
+}
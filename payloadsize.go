@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc/stats"
+)
+
+var exportPayloadSize metric.Int64Histogram
+
+func init() {
+	var err error
+	exportPayloadSize, err = meter.Int64Histogram("otel.exporter.payload.size",
+		metric.WithDescription("신호별 OTLP 내보내기 요청 한 건의 직렬화 크기"),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// otlpSignals는 OTLP 수집기 gRPC 메서드를 신호 이름에 대응시킵니다.
+var otlpSignals = map[string]string{
+	"/opentelemetry.proto.collector.trace.v1.TraceService/Export":     "traces",
+	"/opentelemetry.proto.collector.metrics.v1.MetricsService/Export": "metrics",
+	"/opentelemetry.proto.collector.logs.v1.LogsService/Export":       "logs",
+}
+
+type signalKey struct{}
+
+// payloadSizeHandler는 OTLP 익스포터 연결에 붙는 gRPC stats.Handler입니다.
+// 압축 전 protobuf 직렬화 크기를 기록하므로 값은 전송 방식(gRPC/HTTP)과 관계없이
+// 같은 요청이면 같고, 수집기 대역폭과 배치 크기를 가늠하는 데 쓸 수 있습니다.
+type payloadSizeHandler struct{}
+
+func (payloadSizeHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	if signal, ok := otlpSignals[info.FullMethodName]; ok {
+		return context.WithValue(ctx, signalKey{}, signal)
+	}
+	return ctx
+}
+
+func (payloadSizeHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	out, ok := s.(*stats.OutPayload)
+	if !ok || !out.IsClient() {
+		return
+	}
+	signal, ok := ctx.Value(signalKey{}).(string)
+	if !ok {
+		return
+	}
+	exportPayloadSize.Record(ctx, int64(out.Length),
+		metric.WithAttributes(attribute.String("signal", signal)))
+}
+
+func (payloadSizeHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (payloadSizeHandler) HandleConn(context.Context, stats.ConnStats) {}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// reportedQuantiles는 히스토그램마다 함께 내보낼 백분위수입니다.
+var reportedQuantiles = []float64{0.5, 0.9, 0.99}
+
+// percentileExporter는 내보내는 히스토그램마다 버킷 개수로 추정한 p50, p90, p99를
+// "<이름>.p50" 같은 게이지로 덧붙입니다. 백엔드가 백분위수를 계산할 수 없는 데모용입니다.
+//
+// 추정은 값이 버킷 안에 고르게 퍼져 있다고 보고 선형 보간하므로, 오차는 해당 백분위수가
+// 떨어지는 버킷의 폭만큼까지 날 수 있습니다. 첫 버킷의 하한과 마지막(+Inf) 버킷의 상한은
+// 기록된 최솟값과 최댓값으로 대신합니다. 버킷이 성기면 추정값은 최솟값과 최댓값 사이의
+// 보간에 가까워집니다. 버킷 정보를 빼는 설정(MetricsDropBuckets)과는 함께 쓸 수 없습니다.
+type percentileExporter struct {
+	metric.Exporter
+}
+
+func (e percentileExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	out := *rm
+	out.ScopeMetrics = make([]metricdata.ScopeMetrics, len(rm.ScopeMetrics))
+	for i, sm := range rm.ScopeMetrics {
+		metrics := slices.Clone(sm.Metrics)
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[int64]:
+				metrics = append(metrics, percentileGauges(m, data.DataPoints)...)
+			case metricdata.Histogram[float64]:
+				metrics = append(metrics, percentileGauges(m, data.DataPoints)...)
+			}
+		}
+		sm.Metrics = metrics
+		out.ScopeMetrics[i] = sm
+	}
+	return e.Exporter.Export(ctx, &out)
+}
+
+// percentileGauges는 히스토그램 데이터 포인트마다 백분위수 게이지를 만듭니다.
+func percentileGauges[N int64 | float64](m metricdata.Metrics, points []metricdata.HistogramDataPoint[N]) []metricdata.Metrics {
+	out := make([]metricdata.Metrics, 0, len(reportedQuantiles))
+	for _, q := range reportedQuantiles {
+		gauge := metricdata.Gauge[float64]{}
+		for _, dp := range points {
+			if dp.Count == 0 {
+				continue
+			}
+			gauge.DataPoints = append(gauge.DataPoints, metricdata.DataPoint[float64]{
+				Attributes: dp.Attributes,
+				StartTime:  dp.StartTime,
+				Time:       dp.Time,
+				Value:      estimateQuantile(dp, q),
+			})
+		}
+		out = append(out, metricdata.Metrics{
+			Name:        fmt.Sprintf("%s.p%g", m.Name, q*100),
+			Description: fmt.Sprintf("%s의 버킷으로 추정한 %g 백분위수", m.Name, q*100),
+			Unit:        m.Unit,
+			Data:        gauge,
+		})
+	}
+	return out
+}
+
+// estimateQuantile은 버킷 개수로 q 분위수를 선형 보간해 추정합니다.
+func estimateQuantile[N int64 | float64](dp metricdata.HistogramDataPoint[N], q float64) float64 {
+	lo, hi := math.Inf(-1), math.Inf(1)
+	if v, ok := dp.Min.Value(); ok {
+		lo = float64(v)
+	}
+	if v, ok := dp.Max.Value(); ok {
+		hi = float64(v)
+	}
+
+	rank := q * float64(dp.Count)
+	var cum float64
+	for i, n := range dp.BucketCounts {
+		if n == 0 {
+			continue
+		}
+		lower, upper := lo, hi
+		if i > 0 {
+			lower = max(dp.Bounds[i-1], lo)
+		}
+		if i < len(dp.Bounds) {
+			upper = min(dp.Bounds[i], hi)
+		}
+		if cum+float64(n) >= rank {
+			// 최솟값/최댓값이 없으면 무한대 쪽 경계는 반대쪽 경계로 대신합니다.
+			if math.IsInf(lower, -1) {
+				return upper
+			}
+			if math.IsInf(upper, 1) {
+				return lower
+			}
+			return lower + (upper-lower)*(rank-cum)/float64(n)
+		}
+		cum += float64(n)
+	}
+	return hi
+}
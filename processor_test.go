@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestProcessorSelection(t *testing.T) {
+	// 단순 프로세서는 끝나자마자 내보내고, 배치 프로세서는 간격(데모 모드 1초)이 지나거나
+	// ForceFlush할 때까지 모아 둡니다.
+	tests := []struct {
+		processor     string
+		wantImmediate bool
+	}{
+		{"simple", true},
+		{"batch", false},
+	}
+	for _, tt := range tests {
+		t.Run("traces/"+tt.processor, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.TracesProcessor = tt.processor
+			exp := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(newSpanProcessor(cfg, exp)))
+			defer tp.Shutdown(context.Background())
+
+			_, span := tp.Tracer("test").Start(context.Background(), "span")
+			span.End()
+			if got := len(exp.GetSpans()) == 1; got != tt.wantImmediate {
+				t.Errorf("End 직후 내보냄 = %v, want %v", got, tt.wantImmediate)
+			}
+			if err := tp.ForceFlush(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if n := len(exp.GetSpans()); n != 1 {
+				t.Errorf("ForceFlush 뒤 스팬 %d개, want 1", n)
+			}
+		})
+		t.Run("logs/"+tt.processor, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.LogsProcessor = tt.processor
+			rec := &logRecorder{}
+			lp := newLoggerProvider(cfg, []log.Exporter{rec}, resource.Empty())
+			defer lp.Shutdown(context.Background())
+
+			var r otellog.Record
+			r.SetSeverity(otellog.SeverityInfo)
+			r.SetBody(otellog.StringValue("hello"))
+			lp.Logger("test").Emit(context.Background(), r)
+			if got := len(rec.get()) == 1; got != tt.wantImmediate {
+				t.Errorf("Emit 직후 내보냄 = %v, want %v", got, tt.wantImmediate)
+			}
+			if err := lp.ForceFlush(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if n := len(rec.get()); n != 1 {
+				t.Errorf("ForceFlush 뒤 레코드 %d개, want 1", n)
+			}
+		})
+	}
+}
+
+func TestProcessorConfig(t *testing.T) {
+	tests := []struct {
+		env     string
+		wantErr string
+	}{
+		{"OTEL_SAMPLE_TRACES_PROCESSOR", `OTEL_SAMPLE_TRACES_PROCESSOR: "batch" 또는 "simple"이어야 합니다: "sync"`},
+		{"OTEL_SAMPLE_LOGS_PROCESSOR", `OTEL_SAMPLE_LOGS_PROCESSOR: "batch" 또는 "simple"이어야 합니다: "sync"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv(tt.env, "sync")
+			_, err := loadConfig(nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("에러 = %v, want %q 포함", err, tt.wantErr)
+			}
+		})
+	}
+}
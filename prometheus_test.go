@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// gatheredValue는 reg에서 이름이 family인 카운터의 값을 모두 더합니다. 없으면 ok가 false입니다.
+func gatheredValue(t *testing.T, reg prometheus.Gatherer, family string) (float64, bool) {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() != family {
+			continue
+		}
+		var v float64
+		for _, m := range f.GetMetric() {
+			v += m.GetCounter().GetValue()
+		}
+		return v, true
+	}
+	return 0, false
+}
+
+func TestPrometheusRegisterer(t *testing.T) {
+	// 제공자마다 다른 레지스트리를 넘기면 각 레지스트리는 자기 제공자의 값만 봅니다.
+	tests := []struct {
+		name string
+		adds int64
+	}{
+		{"first", 1},
+		{"second", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := prometheus.NewRegistry()
+			cfg := defaultConfig()
+			cfg.PrometheusRegisterer = reg
+			mp, err := newMeterProvider(cfg, nil, resource.Empty())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mp.Shutdown(context.Background())
+
+			counter, err := mp.Meter("test").Int64Counter("registry.hits")
+			if err != nil {
+				t.Fatal(err)
+			}
+			counter.Add(context.Background(), tt.adds)
+
+			got, ok := gatheredValue(t, reg, "dice_game_registry_hits_total")
+			if !ok || got != float64(tt.adds) {
+				t.Errorf("dice_game_registry_hits_total = %v (있음 %v), want %d", got, ok, tt.adds)
+			}
+			if _, ok := gatheredValue(t, prometheus.DefaultGatherer, "dice_game_registry_hits_total"); ok {
+				t.Error("기본 레지스트리에도 등록되었습니다")
+			}
+		})
+	}
+}
+
+func TestPrometheusExemplars(t *testing.T) {
+	h := newTestHandler(defaultConfig())
+	tests := []struct {
+		name          string
+		accept        string
+		wantType      string
+		wantExemplars bool
+	}{
+		{"openmetrics", "application/openmetrics-text; version=1.0.0; charset=utf-8", "application/openmetrics-text", true},
+		// 텍스트 형식은 예시를 실을 수 없습니다.
+		{"text", "", "text/plain", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetTelemetry(t)
+			if w := serve(h, httptest.NewRequest(http.MethodGet, "/rolldice/exemplar", nil)); w.Code != http.StatusOK {
+				t.Fatalf("상태 = %d, want 200", w.Code)
+			}
+			traceID := findSpan(t, "roll").SpanContext.TraceID().String()
+
+			r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := serve(h, r)
+			if w.Code != http.StatusOK {
+				t.Fatalf("/metrics 상태 = %d", w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.wantType) {
+				t.Errorf("Content-Type = %q, want %s", ct, tt.wantType)
+			}
+
+			// 방금 요청이 들어간 히스토그램 버킷에 그 요청의 trace_id가 예시로 붙어야 합니다.
+			// 다른 버킷에는 앞선 요청의 예시가 남아 있을 수 있습니다.
+			// 예시 레이블 순서는 맵 순회 순서라 span_id가 앞에 올 수도 있습니다.
+			var got bool
+			for _, line := range strings.Split(w.Body.String(), "\n") {
+				if !strings.HasPrefix(line, "dice_game_dice_roll_duration") || !strings.Contains(line, `player="exemplar"`) {
+					continue
+				}
+				if _, ex, ok := strings.Cut(line, " # {"); ok && strings.Contains(ex, `trace_id="`+traceID+`"`) {
+					got = true
+				}
+			}
+			if got != tt.wantExemplars {
+				t.Errorf("trace_id=%s인 예시 있음 = %v, want %v", traceID, got, tt.wantExemplars)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestBaggagePropagation(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+	}{
+		{"on", true},
+		{"off", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.BaggagePropagation = tt.enabled
+			prop := newPropagator(cfg)
+
+			in := http.Header{}
+			in.Set("baggage", "tenant=acme")
+			ctx := prop.Extract(context.Background(), propagation.HeaderCarrier(in))
+			if got := baggage.FromContext(ctx).Member("tenant").Value() == "acme"; got != tt.enabled {
+				t.Errorf("들어온 배기지를 읽음 = %v, want %v", got, tt.enabled)
+			}
+
+			m, _ := baggage.NewMember("tenant", "acme")
+			bag, _ := baggage.New(m)
+			out := http.Header{}
+			prop.Inject(baggage.ContextWithBaggage(context.Background(), bag), propagation.HeaderCarrier(out))
+			if got := out.Get("baggage") != ""; got != tt.enabled {
+				t.Errorf("나가는 요청에 baggage 헤더를 실음 = %v, want %v", got, tt.enabled)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+// publisher는 메시지 큐에 메시지 하나를 발행합니다.
+// 새 큐 백엔드는 이 인터페이스만 구현하면 publishSpanExporter를 그대로 쓸 수 있습니다.
+type publisher interface {
+	Publish(ctx context.Context, data []byte) error
+	Close(ctx context.Context) error
+}
+
+// publishSpanExporter는 스팬 배치를 OTLP JSON(ExportTraceServiceRequest) 메시지 하나로
+// 발행합니다. OTLP 수집기 대신 큐를 구독하는 자체 파이프라인에서 처리할 때 씁니다.
+type publishSpanExporter struct {
+	pub publisher
+}
+
+func newPublishSpanExporter(pub publisher) *publishSpanExporter {
+	return &publishSpanExporter{pub: pub}
+}
+
+func (e *publishSpanExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	b, err := marshalOTLPJSON(&coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: spansToProto(spans),
+	})
+	if err != nil {
+		return err
+	}
+	return e.pub.Publish(ctx, b)
+}
+
+func (e *publishSpanExporter) Shutdown(ctx context.Context) error {
+	return e.pub.Close(ctx)
+}
+
+// natsPublisher는 NATS 주제(subject)로 발행합니다.
+type natsPublisher struct {
+	conn    *nats.Conn
+	subject string
+}
+
+func newNATSPublisher(url, subject string) (*natsPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name(name))
+	if err != nil {
+		return nil, err
+	}
+	return &natsPublisher{conn: conn, subject: subject}, nil
+}
+
+func (p *natsPublisher) Publish(_ context.Context, data []byte) error {
+	return p.conn.Publish(p.subject, data)
+}
+
+// Close는 아직 보내지 못한 메시지를 모두 보낸 뒤 연결을 닫습니다.
+func (p *natsPublisher) Close(ctx context.Context) error {
+	if err := p.conn.FlushWithContext(ctx); err != nil {
+		p.conn.Close()
+		return err
+	}
+	p.conn.Close()
+	return nil
+}
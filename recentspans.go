@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+)
+
+// maxRecentSpans는 /admin/traces가 보관하는 최근 스팬 수의 상한입니다.
+// 스팬 하나가 수 KB라면 최대 수 MB를 씁니다.
+const maxRecentSpans = 1000
+
+// recentSpans는 /admin/traces가 보여 줄 최근에 끝난 스팬입니다.
+// 관리 모드에서 OTEL_SAMPLE_ADMIN_TRACES가 켜져 있을 때만 추적 제공자에 등록됩니다.
+var recentSpans = newSpanRing(maxRecentSpans)
+
+// spanRing은 끝난 스팬을 최대 size개까지 보관하는 SpanProcessor입니다. 가득 차면 가장
+// 오래된 스팬을 덮어씁니다. 백엔드 없이 로컬에서 추적을 확인하는 개발용이며, 표본 추출된
+// 스팬만 보입니다.
+type spanRing struct {
+	mu    sync.Mutex
+	spans []trace.ReadOnlySpan
+	next  int
+	size  int
+}
+
+func newSpanRing(size int) *spanRing {
+	return &spanRing{size: size}
+}
+
+func (r *spanRing) OnStart(context.Context, trace.ReadWriteSpan) {}
+
+func (r *spanRing) OnEnd(s trace.ReadOnlySpan) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.spans) < r.size {
+		r.spans = append(r.spans, s)
+		return
+	}
+	r.spans[r.next] = s
+	r.next = (r.next + 1) % r.size
+}
+
+func (r *spanRing) Shutdown(context.Context) error   { return nil }
+func (r *spanRing) ForceFlush(context.Context) error { return nil }
+
+// snapshot은 보관 중인 스팬을 끝난 순서대로 반환합니다.
+func (r *spanRing) snapshot() []trace.ReadOnlySpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]trace.ReadOnlySpan, 0, len(r.spans))
+	out = append(out, r.spans[r.next:]...)
+	return append(out, r.spans[:r.next]...)
+}
+
+// serveRecentTraces는 최근에 끝난 스팬을 OTLP JSON(ExportTraceServiceRequest)으로 반환합니다.
+// trace_id 쿼리 파라미터가 있으면 그 추적의 스팬만 반환합니다. 응답은 -replay 파일로도 쓸 수 있습니다.
+func serveRecentTraces(w http.ResponseWriter, r *http.Request) {
+	spans := recentSpans.snapshot()
+	if tid := r.URL.Query().Get("trace_id"); tid != "" {
+		kept := spans[:0]
+		for _, s := range spans {
+			if s.SpanContext().TraceID().String() == tid {
+				kept = append(kept, s)
+			}
+		}
+		spans = kept
+	}
+
+	b, err := marshalOTLPJSON(&coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: spansToProto(spans),
+	})
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(b, '\n'))
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	llog "log"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// replaySpans는 otlpjson 형식으로 기록된 스팬 파일을 읽어 설정된 익스포터로 다시 내보냅니다.
+// 가장 늦게 끝난 스팬이 지금 끝난 것처럼 모든 타임스탬프를 같은 만큼 옮기고,
+// 추적/스팬 ID는 새로 발급하되 같은 원본 ID는 같은 새 ID로 바꿔 부모-자식 관계를 유지합니다.
+// 따라서 같은 파일을 여러 번 재생해도 백엔드에서 추적이 겹치지 않습니다.
+func replaySpans(ctx context.Context, cfg config) (err error) {
+	reqs, err := readOTLPJSONFile(cfg.ReplayFile)
+	if err != nil {
+		return err
+	}
+
+	conn, err := newOTLPConn(cfg)
+	if err != nil {
+		return err
+	}
+	if conn != nil {
+		defer func() {
+			err = errors.Join(err, conn.Close())
+		}()
+	}
+	exporter, err := newTraceExporter(ctx, cfg, conn)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, exporter.Shutdown(context.Background()))
+	}()
+
+	shift := time.Since(latestEndTime(reqs))
+	ids := newIDRemapper()
+	var total int
+	for _, req := range reqs {
+		spans, err := protoToSpans(req.GetResourceSpans(), shift, ids)
+		if err != nil {
+			return err
+		}
+		if err := exporter.ExportSpans(ctx, spans); err != nil {
+			return err
+		}
+		total += len(spans)
+	}
+	llog.Printf("%s에서 스팬 %d개를 재생했습니다", cfg.ReplayFile, total)
+	return nil
+}
+
+// readOTLPJSONFile은 연속된 OTLP JSON ExportTraceServiceRequest 값을 읽습니다.
+// 한 줄짜리와 들여쓴 형식을 모두 받아들입니다.
+func readOTLPJSONFile(path string) ([]*coltracepb.ExportTraceServiceRequest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var reqs []*coltracepb.ExportTraceServiceRequest
+	dec := json.NewDecoder(f)
+	dec.UseNumber()
+	for {
+		var v any
+		if err := dec.Decode(&v); errors.Is(err, io.EOF) {
+			return reqs, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		req, err := unmarshalOTLPJSON(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		reqs = append(reqs, req)
+	}
+}
+
+// unmarshalOTLPJSON은 marshalOTLPJSON의 역변환입니다.
+func unmarshalOTLPJSON(v any) (*coltracepb.ExportTraceServiceRequest, error) {
+	err := recodeIDs(v, func(s string) (string, error) {
+		raw, err := hex.DecodeString(s)
+		return base64.StdEncoding.EncodeToString(raw), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	req := &coltracepb.ExportTraceServiceRequest{}
+	if err := protojson.Unmarshal(b, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func latestEndTime(reqs []*coltracepb.ExportTraceServiceRequest) time.Time {
+	var latest uint64
+	for _, req := range reqs {
+		for _, rs := range req.GetResourceSpans() {
+			for _, ss := range rs.GetScopeSpans() {
+				for _, s := range ss.GetSpans() {
+					latest = max(latest, s.GetEndTimeUnixNano())
+				}
+			}
+		}
+	}
+	if latest == 0 {
+		return time.Now()
+	}
+	return time.Unix(0, int64(latest))
+}
+
+// protoToSpans는 spansToProto의 역변환으로 OTLP 스팬을 SDK의 ReadOnlySpan으로 되돌립니다.
+func protoToSpans(rss []*tracepb.ResourceSpans, shift time.Duration, ids *idRemapper) ([]trace.ReadOnlySpan, error) {
+	var stubs tracetest.SpanStubs
+	for _, rs := range rss {
+		res := resource.NewWithAttributes(rs.GetSchemaUrl(), protoToAttributes(rs.GetResource().GetAttributes())...)
+		for _, ss := range rs.GetScopeSpans() {
+			scope := instrumentation.Scope{
+				Name:      ss.GetScope().GetName(),
+				Version:   ss.GetScope().GetVersion(),
+				SchemaURL: ss.GetSchemaUrl(),
+			}
+			for _, s := range ss.GetSpans() {
+				stub, err := protoToSpanStub(s, shift, ids)
+				if err != nil {
+					return nil, err
+				}
+				stub.Resource = res
+				stub.InstrumentationScope = scope
+				stubs = append(stubs, stub)
+			}
+		}
+	}
+	return stubs.Snapshots(), nil
+}
+
+func protoToSpanStub(s *tracepb.Span, shift time.Duration, ids *idRemapper) (tracetest.SpanStub, error) {
+	ts, err := oteltrace.ParseTraceState(s.GetTraceState())
+	if err != nil {
+		return tracetest.SpanStub{}, err
+	}
+	tid := ids.traceID(s.GetTraceId())
+	stub := tracetest.SpanStub{
+		Name: s.GetName(),
+		SpanContext: oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+			TraceID:    tid,
+			SpanID:     ids.spanID(s.GetSpanId()),
+			TraceFlags: oteltrace.TraceFlags(s.GetFlags()) | oteltrace.FlagsSampled,
+			TraceState: ts,
+		}),
+		SpanKind:          oteltrace.SpanKind(s.GetKind()),
+		StartTime:         shiftTime(s.GetStartTimeUnixNano(), shift),
+		EndTime:           shiftTime(s.GetEndTimeUnixNano(), shift),
+		Attributes:        protoToAttributes(s.GetAttributes()),
+		DroppedAttributes: int(s.GetDroppedAttributesCount()),
+		DroppedEvents:     int(s.GetDroppedEventsCount()),
+		DroppedLinks:      int(s.GetDroppedLinksCount()),
+		Status:            protoToStatus(s.GetStatus()),
+	}
+	if len(s.GetParentSpanId()) > 0 {
+		stub.Parent = oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+			TraceID:    tid,
+			SpanID:     ids.spanID(s.GetParentSpanId()),
+			TraceFlags: oteltrace.FlagsSampled,
+		})
+	}
+	for _, ev := range s.GetEvents() {
+		stub.Events = append(stub.Events, trace.Event{
+			Name:                  ev.GetName(),
+			Time:                  shiftTime(ev.GetTimeUnixNano(), shift),
+			Attributes:            protoToAttributes(ev.GetAttributes()),
+			DroppedAttributeCount: int(ev.GetDroppedAttributesCount()),
+		})
+	}
+	for _, l := range s.GetLinks() {
+		stub.Links = append(stub.Links, trace.Link{
+			SpanContext: oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+				TraceID:    ids.traceID(l.GetTraceId()),
+				SpanID:     ids.spanID(l.GetSpanId()),
+				TraceFlags: oteltrace.TraceFlags(l.GetFlags()),
+			}),
+			Attributes:            protoToAttributes(l.GetAttributes()),
+			DroppedAttributeCount: int(l.GetDroppedAttributesCount()),
+		})
+	}
+	return stub, nil
+}
+
+func shiftTime(unixNano uint64, shift time.Duration) time.Time {
+	return time.Unix(0, int64(unixNano)).Add(shift)
+}
+
+func protoToStatus(st *tracepb.Status) trace.Status {
+	switch st.GetCode() {
+	case tracepb.Status_STATUS_CODE_OK:
+		return trace.Status{Code: codes.Ok}
+	case tracepb.Status_STATUS_CODE_ERROR:
+		return trace.Status{Code: codes.Error, Description: st.GetMessage()}
+	default:
+		return trace.Status{Code: codes.Unset}
+	}
+}
+
+func protoToAttributes(kvs []*commonpb.KeyValue) []attribute.KeyValue {
+	out := make([]attribute.KeyValue, 0, len(kvs))
+	for _, kv := range kvs {
+		out = append(out, attribute.KeyValue{
+			Key:   attribute.Key(kv.GetKey()),
+			Value: protoToAttributeValue(kv.GetValue()),
+		})
+	}
+	return out
+}
+
+func protoToAttributeValue(v *commonpb.AnyValue) attribute.Value {
+	switch v := v.GetValue().(type) {
+	case *commonpb.AnyValue_BoolValue:
+		return attribute.BoolValue(v.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return attribute.Int64Value(v.IntValue)
+	case *commonpb.AnyValue_DoubleValue:
+		return attribute.Float64Value(v.DoubleValue)
+	case *commonpb.AnyValue_StringValue:
+		return attribute.StringValue(v.StringValue)
+	case *commonpb.AnyValue_ArrayValue:
+		return protoToArrayValue(v.ArrayValue.GetValues())
+	default:
+		return attribute.StringValue("")
+	}
+}
+
+// protoToArrayValue는 동일한 타입의 배열만 SDK 속성 슬라이스로 되돌립니다.
+// 첫 원소의 타입을 기준으로 하며, 섞인 배열은 문자열 슬라이스로 바꿉니다.
+func protoToArrayValue(vs []*commonpb.AnyValue) attribute.Value {
+	if len(vs) == 0 {
+		return attribute.StringSliceValue(nil)
+	}
+	switch vs[0].GetValue().(type) {
+	case *commonpb.AnyValue_BoolValue:
+		out := make([]bool, 0, len(vs))
+		for _, v := range vs {
+			out = append(out, v.GetBoolValue())
+		}
+		return attribute.BoolSliceValue(out)
+	case *commonpb.AnyValue_IntValue:
+		out := make([]int64, 0, len(vs))
+		for _, v := range vs {
+			out = append(out, v.GetIntValue())
+		}
+		return attribute.Int64SliceValue(out)
+	case *commonpb.AnyValue_DoubleValue:
+		out := make([]float64, 0, len(vs))
+		for _, v := range vs {
+			out = append(out, v.GetDoubleValue())
+		}
+		return attribute.Float64SliceValue(out)
+	default:
+		out := make([]string, 0, len(vs))
+		for _, v := range vs {
+			out = append(out, protoToAttributeValue(v).Emit())
+		}
+		return attribute.StringSliceValue(out)
+	}
+}
+
+// idRemapper는 원본 ID를 새 임의 ID로 일관되게 바꿉니다.
+// nil idRemapper는 원본 ID를 그대로 씁니다.
+type idRemapper struct {
+	traces map[string]oteltrace.TraceID
+	spans  map[string]oteltrace.SpanID
+}
+
+func newIDRemapper() *idRemapper {
+	return &idRemapper{
+		traces: map[string]oteltrace.TraceID{},
+		spans:  map[string]oteltrace.SpanID{},
+	}
+}
+
+func (m *idRemapper) traceID(orig []byte) oteltrace.TraceID {
+	if m == nil {
+		var id oteltrace.TraceID
+		copy(id[:], orig)
+		return id
+	}
+	if id, ok := m.traces[string(orig)]; ok {
+		return id
+	}
+	var id oteltrace.TraceID
+	for !id.IsValid() || bytes.Equal(id[:], orig) {
+		_, _ = rand.Read(id[:])
+	}
+	m.traces[string(orig)] = id
+	return id
+}
+
+func (m *idRemapper) spanID(orig []byte) oteltrace.SpanID {
+	if m == nil {
+		var id oteltrace.SpanID
+		copy(id[:], orig)
+		return id
+	}
+	if id, ok := m.spans[string(orig)]; ok {
+		return id
+	}
+	var id oteltrace.SpanID
+	for !id.IsValid() || bytes.Equal(id[:], orig) {
+		_, _ = rand.Read(id[:])
+	}
+	m.spans[string(orig)] = id
+	return id
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// version은 service.version 리소스 속성입니다. 빌드할 때
+// -ldflags "-X main.version=1.2.3"으로 지정하며, OTEL_RESOURCE_ATTRIBUTES로도 덮어쓸 수 있습니다.
+var version = "dev"
+
+// defaultServiceName은 OTEL_SERVICE_NAME이 없을 때 쓰는 서비스 이름입니다.
+const defaultServiceName = "dice-game"
+
+// resourceDetectors는 OTEL_SAMPLE_RESOURCE_DETECTORS로 고를 수 있는 감지기입니다.
+var resourceDetectors = map[string]resource.Option{
+	"host":      resource.WithHost(),
+	"os":        resource.WithOS(),
+	"process":   resource.WithProcess(),
+	"container": resource.WithContainer(),
+}
+
+// newResource는 세 신호가 함께 쓸 리소스를 만듭니다.
+//
+// 같은 키가 여러 곳에 있으면 뒤의 것이 이깁니다. 우선순위는
+// SDK 기본값 < 서비스 기본값 < 감지기 < 리소스 속성 파일 < OTEL_RESOURCE_ATTRIBUTES, OTEL_SERVICE_NAME 순으로,
+// 운영자가 직접 지정한 값일수록 높습니다. 예를 들어 감지기가 찾은 host.name은
+// 파일의 host.name에, 파일의 service.name은 OTEL_SERVICE_NAME에 덮어쓰입니다.
+//
+// 서비스 기본값은 service.name("dice-game"), service.version(version),
+// service.instance.id(프로세스마다 새로 만든 UUID), 그리고 설정했다면
+// deployment.environment(cfg.DeploymentEnvironment)입니다.
+//
+// 파일은 오케스트레이터가 메타데이터를 파일로 마운트하는 환경을 위한 것으로,
+// 파일이 없으면 기록만 남기고 나머지 출처로 계속합니다.
+func newResource(ctx context.Context, cfg config) (*resource.Resource, error) {
+	instanceID, err := newInstanceID()
+	if err != nil {
+		return nil, err
+	}
+	serviceAttrs := []attribute.KeyValue{
+		semconv.ServiceName(defaultServiceName),
+		semconv.ServiceVersion(version),
+		semconv.ServiceInstanceID(instanceID),
+	}
+	if cfg.DeploymentEnvironment != "" {
+		serviceAttrs = append(serviceAttrs, semconv.DeploymentEnvironment(cfg.DeploymentEnvironment))
+	}
+	layers := []*resource.Resource{
+		resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, serviceAttrs...),
+	}
+
+	if len(cfg.ResourceDetectors) > 0 {
+		layers = append(layers, detectResources(ctx, cfg.ResourceDetectors, cfg.ResourceDetectTimeout)...)
+	}
+
+	if cfg.ResourceAttributesFile != "" {
+		attrs, err := readResourceAttributesFile(cfg.ResourceAttributesFile)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			log.Printf("리소스 속성 파일이 없어 건너뜁니다: %s", cfg.ResourceAttributesFile)
+		case err != nil:
+			return nil, err
+		default:
+			layers = append(layers, resource.NewSchemaless(attrs...))
+		}
+	}
+
+	// resource.Default()도 환경 변수를 읽지만, 감지기와 파일보다 앞서야 하므로 마지막에 다시 덮어씁니다.
+	fromEnv, err := resource.New(ctx, resource.WithFromEnv())
+	if err != nil {
+		return nil, err
+	}
+	layers = append(layers, fromEnv)
+
+	// 출처마다 스키마 URL이 다르면 Merge가 실패합니다. 조용히 한쪽을 버리지 않고 에러로 알립니다.
+	res := layers[0]
+	for _, layer := range layers[1:] {
+		if res, err = resource.Merge(res, layer); err != nil {
+			return nil, fmt.Errorf("리소스 병합: %w", err)
+		}
+	}
+	return res, nil
+}
+
+// detectResources는 names의 감지기를 동시에 실행하고 찾은 리소스를 names 순서대로 반환합니다.
+// 감지기는 클라우드 메타데이터 엔드포인트처럼 느리거나 멈출 수 있으므로, timeout(0이면 무제한)
+// 안에 끝나지 않거나 실패한 감지기는 로그를 남기고 건너뜁니다. 일부만 실패해도(예: 컨테이너 밖)
+// 찾은 속성은 씁니다. 건너뛴 감지기의 고루틴은 ctx를 무시할 수 있으므로 뒤에서 끝나도록 둡니다.
+func detectResources(ctx context.Context, names []string, timeout time.Duration) []*resource.Resource {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		res *resource.Resource
+		err error
+	}
+	results := make([]chan result, len(names))
+	for i, name := range names {
+		// 건너뛴 감지기가 나중에 끝나도 막히지 않도록 버퍼를 둡니다.
+		results[i] = make(chan result, 1)
+		go func() {
+			res, err := resource.New(ctx, resourceDetectors[name])
+			results[i] <- result{res, err}
+		}()
+	}
+
+	var (
+		detected []*resource.Resource
+		timedOut []string
+	)
+	for i, name := range names {
+		var r result
+		select {
+		case r = <-results[i]:
+		case <-ctx.Done():
+			// 마감과 동시에 끝난 감지기는 결과를 씁니다.
+			select {
+			case r = <-results[i]:
+			default:
+				timedOut = append(timedOut, name)
+				continue
+			}
+		}
+		switch {
+		case errors.Is(r.err, resource.ErrPartialResource):
+			log.Printf("리소스 감지기 %q 일부 실패: %v", name, r.err)
+		case r.err != nil:
+			log.Printf("리소스 감지기 %q가 실패해 건너뜁니다: %v", name, r.err)
+			continue
+		}
+		detected = append(detected, r.res)
+	}
+	if len(timedOut) > 0 {
+		log.Printf("리소스 감지기 %v가 제한 시간 %v 안에 끝나지 않아 건너뛰었습니다", timedOut, timeout)
+	}
+	return detected
+}
+
+// newInstanceID는 service.instance.id로 쓸 무작위 UUID(버전 4)를 만듭니다.
+// 같은 호스트에서 여러 인스턴스가 떠도, 재시작해도 겹치지 않습니다.
+func newInstanceID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// readResourceAttributesFile은 "key=value" 줄로 된 파일을 읽습니다.
+// 빈 줄과 "#"으로 시작하는 줄은 건너뜁니다.
+func readResourceAttributesFile(path string) ([]attribute.KeyValue, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var attrs []attribute.KeyValue
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("%s:%d: key=value 형식이 아닙니다: %q", path, n, line)
+		}
+		attrs = append(attrs, attribute.String(strings.TrimSpace(k), strings.TrimSpace(v)))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return attrs, nil
+}
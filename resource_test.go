@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	stdlog "log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// resourceValue는 리소스 속성 key의 문자열 값을 반환합니다.
+func resourceValue(res *resource.Resource, key attribute.Key) string {
+	v, _ := res.Set().Value(key)
+	return v.AsString()
+}
+
+func TestResourcePrecedence(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "resource.txt")
+	if err := os.WriteFile(file, []byte("# 마운트된 메타데이터\nservice.name=file-svc\nteam = dice\n\nhost.name=file-host\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		file         string
+		envAttrs     string
+		envService   string
+		detectors    []string
+		want         map[attribute.Key]string
+		wantNonEmpty []attribute.Key
+	}{
+		{
+			name: "defaults",
+			want: map[attribute.Key]string{"service.name": defaultServiceName, "service.version": version},
+		},
+		{
+			name: "file over defaults",
+			file: file,
+			want: map[attribute.Key]string{"service.name": "file-svc", "team": "dice", "host.name": "file-host"},
+		},
+		{
+			// 파일의 host.name이 감지기가 찾은 값을 덮어씁니다.
+			name:      "file over detectors",
+			file:      file,
+			detectors: []string{"host"},
+			want:      map[attribute.Key]string{"host.name": "file-host"},
+		},
+		{
+			name:         "detector without file",
+			detectors:    []string{"host"},
+			wantNonEmpty: []attribute.Key{"host.name"},
+		},
+		{
+			name:     "env attributes over file",
+			file:     file,
+			envAttrs: "team=platform,service.version=9.9.9",
+			want:     map[attribute.Key]string{"team": "platform", "service.version": "9.9.9", "service.name": "file-svc"},
+		},
+		{
+			name:       "service name env over everything",
+			file:       file,
+			envAttrs:   "service.name=attrs-svc",
+			envService: "env-svc",
+			want:       map[attribute.Key]string{"service.name": "env-svc"},
+		},
+		{
+			name: "missing file is skipped",
+			file: filepath.Join(dir, "missing.txt"),
+			want: map[attribute.Key]string{"service.name": defaultServiceName},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_RESOURCE_ATTRIBUTES", tt.envAttrs)
+			t.Setenv("OTEL_SERVICE_NAME", tt.envService)
+			cfg := defaultConfig()
+			cfg.ResourceAttributesFile = tt.file
+			cfg.ResourceDetectors = tt.detectors
+			res, err := newResource(context.Background(), cfg)
+			if err != nil {
+				t.Fatalf("newResource: %v", err)
+			}
+			for key, want := range tt.want {
+				if got := resourceValue(res, key); got != want {
+					t.Errorf("%s = %q, want %q", key, got, want)
+				}
+			}
+			for _, key := range append(tt.wantNonEmpty, "service.instance.id") {
+				if resourceValue(res, key) == "" {
+					t.Errorf("%s가 비어 있습니다", key)
+				}
+			}
+		})
+	}
+}
+
+// uuidV4는 버전 4, RFC 4122 변형 UUID의 형식입니다.
+var uuidV4 = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestResourceServiceAttributes(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "")
+	t.Setenv("OTEL_SERVICE_NAME", "")
+	seen := map[string]bool{}
+	for range 3 {
+		res, err := newResource(context.Background(), defaultConfig())
+		if err != nil {
+			t.Fatalf("newResource: %v", err)
+		}
+		if got := resourceValue(res, "service.name"); got != defaultServiceName {
+			t.Errorf("service.name = %q, want %q", got, defaultServiceName)
+		}
+		if got := resourceValue(res, "service.version"); got != version {
+			t.Errorf("service.version = %q, want %q", got, version)
+		}
+		id := resourceValue(res, "service.instance.id")
+		if !uuidV4.MatchString(id) {
+			t.Errorf("service.instance.id %q가 UUIDv4가 아닙니다", id)
+		}
+		// 프로세스마다 새로 만들므로 호출마다 달라야 합니다.
+		if seen[id] {
+			t.Errorf("service.instance.id %q가 겹칩니다", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestProvidersShareResource(t *testing.T) {
+	// 세 신호가 같은 리소스를 달고 내보내야 백엔드에서 한 서비스로 묶입니다.
+	res, err := newResource(context.Background(), defaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := defaultConfig()
+	cfg.TracesProcessor = "simple"
+	cfg.LogsProcessor = "simple"
+
+	spans := tracetest.NewInMemoryExporter()
+	tp, err := newTraceProvider(context.Background(), cfg, res, spans)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tp.Shutdown(context.Background())
+	_, span := tp.Tracer("test").Start(context.Background(), "span")
+	span.End()
+	if got := spans.GetSpans(); len(got) != 1 || !got[0].Resource.Equal(res) {
+		t.Errorf("스팬의 리소스가 공유 리소스가 아닙니다")
+	}
+
+	reader := metric.NewManualReader()
+	cfg.Prometheus = false
+	mp, err := newMeterProvider(cfg, nil, res, metric.WithReader(reader))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mp.Shutdown(context.Background())
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	if !rm.Resource.Equal(res) {
+		t.Errorf("메트릭의 리소스가 공유 리소스가 아닙니다")
+	}
+
+	rec := &logRecorder{}
+	lp := newLoggerProvider(cfg, []log.Exporter{rec}, res)
+	defer lp.Shutdown(context.Background())
+	var r otellog.Record
+	r.SetSeverity(otellog.SeverityInfo)
+	lp.Logger("test").Emit(context.Background(), r)
+	got := rec.get()
+	if len(got) != 1 {
+		t.Fatalf("로그 레코드 %d개, want 1", len(got))
+	}
+	if logRes := got[0].Resource(); !logRes.Equal(res) {
+		t.Errorf("로그의 리소스가 공유 리소스가 아닙니다")
+	}
+}
+
+func TestResourceAttributesFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"missing separator", "team\n", `:1: key=value 형식이 아닙니다: "team"`},
+		{"empty key", "ok=1\n=value\n", `:2: key=value 형식이 아닙니다`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "resource.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg := defaultConfig()
+			cfg.ResourceAttributesFile = path
+			_, err := newResource(context.Background(), cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("에러 = %v, want %q 포함", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// stubDetector는 release가 닫힐 때까지 ctx와 상관없이 멈춰 있다가 attrs나 err을 돌려주는 감지기입니다.
+type stubDetector struct {
+	release <-chan struct{}
+	attrs   []attribute.KeyValue
+	err     error
+}
+
+func (d stubDetector) Detect(context.Context) (*resource.Resource, error) {
+	if d.release != nil {
+		<-d.release
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return resource.NewSchemaless(d.attrs...), nil
+}
+
+// addTestDetector는 테스트가 끝날 때까지 name으로 감지기를 등록합니다.
+func addTestDetector(t *testing.T, name string, d resource.Detector) {
+	t.Helper()
+	resourceDetectors[name] = resource.WithDetectors(d)
+	t.Cleanup(func() { delete(resourceDetectors, name) })
+}
+
+func TestDetectResourcesTimeout(t *testing.T) {
+	hang := make(chan struct{})
+	defer close(hang)
+	addTestDetector(t, "test_fast", stubDetector{attrs: []attribute.KeyValue{attribute.String("fast", "yes")}})
+	addTestDetector(t, "test_second", stubDetector{attrs: []attribute.KeyValue{attribute.String("second", "yes")}})
+	addTestDetector(t, "test_hang", stubDetector{release: hang})
+	addTestDetector(t, "test_broken", stubDetector{err: errors.New("metadata unavailable")})
+
+	var logs bytes.Buffer
+	prev := stdlog.Writer()
+	stdlog.SetOutput(&logs)
+	defer stdlog.SetOutput(prev)
+
+	const timeout = 50 * time.Millisecond
+	start := time.Now()
+	detected := detectResources(context.Background(), []string{"test_hang", "test_fast", "test_broken", "test_second"}, timeout)
+	// 멈춘 감지기를 기다리지 않고 제한 시간 근처에서 돌아와야 합니다.
+	if elapsed := time.Since(start); elapsed > 10*timeout {
+		t.Errorf("detectResources가 %v 걸렸습니다", elapsed)
+	}
+
+	var got []string
+	for _, res := range detected {
+		for _, kv := range res.Attributes() {
+			got = append(got, string(kv.Key))
+		}
+	}
+	if want := []string{"fast", "second"}; !slices.Equal(got, want) {
+		t.Errorf("감지한 속성 = %q, want %q", got, want)
+	}
+	for _, want := range []string{`"test_broken"가 실패해 건너뜁니다`, "[test_hang]가 제한 시간 50ms 안에 끝나지 않아 건너뛰었습니다"} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("로그에 %q가 없습니다: %s", want, logs.String())
+		}
+	}
+}
+
+func TestDetectResourcesNoTimeout(t *testing.T) {
+	// 제한 시간이 0이면 느린 감지기도 끝날 때까지 기다립니다.
+	release := make(chan struct{})
+	addTestDetector(t, "test_slow", stubDetector{release: release, attrs: []attribute.KeyValue{attribute.String("slow", "yes")}})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	detected := detectResources(context.Background(), []string{"test_slow"}, 0)
+	if len(detected) != 1 || resourceValue(detected[0], "slow") != "yes" {
+		t.Errorf("느린 감지기의 결과가 없습니다: %v", detected)
+	}
+}
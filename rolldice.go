@@ -1,58 +1,318 @@
 package main
 
 import (
+	"context"
+	cryptorand "crypto/rand"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
+	"math/big"
 	"math/rand"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 
 	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const name = "go.opentelemetry.io/otel/example/dice"
 
 var (
-	tracer  = otel.Tracer(name)
-	meter   = otel.Meter(name)
-	logger  = otelslog.NewLogger(name)
-	rollCnt metric.Int64Counter
+	tracer = otel.Tracer(name)
+	meter  = otel.Meter(name)
+	logger = slog.New(newLevelHandler(otelslog.NewHandler(name), &logLevel))
 )
 
-func init() {
-	var err error
-	rollCnt, err = meter.Int64Counter("dice.rolls",
-		metric.WithDescription("주사위 값별 던지기 횟수"),
+// diceInstruments는 주사위 핸들러가 쓰는 계측기를 모읍니다.
+//
+// 시작할 때 newDiceInstruments로 한 번만 만들어 핸들러에 넘기므로, 같은 이름의 계측기를
+// 다른 옵션으로 다시 만들어 SDK가 경고와 함께 중복 스트림을 만드는 일을 막습니다.
+// 새 주사위 계측기도 이 구조체에 추가하세요.
+type diceInstruments struct {
+	rolls      metric.Int64Counter
+	rollValues metric.Int64Histogram
+	duration   metric.Float64Histogram
+	randErrors metric.Int64Counter
+}
+
+func newDiceInstruments(m metric.Meter) (*diceInstruments, error) {
+	var (
+		inst diceInstruments
+		err  error
+	)
+	// 개수는 UCUM 주석 단위("{roll}")를 씁니다. 차원은 "1"과 같지만
+	// Prometheus 익스포터가 "1"에 _ratio 접미사를 붙이는 것을 피할 수 있습니다.
+	inst.rolls, err = m.Int64Counter("dice.rolls",
+		metric.WithDescription("주사위 던지기 횟수. roll.value(1~6)와 player 속성으로 나뉩니다"),
 		metric.WithUnit("{roll}"))
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	// 경계를 1~5로 두면 버킷 하나가 주사위 값 하나가 되어 값 분포를 그대로 볼 수 있습니다.
+	inst.rollValues, err = m.Int64Histogram("dice.roll.value",
+		metric.WithDescription("플레이어별 주사위 값(1~6) 분포. 버킷 하나가 값 하나입니다"),
+		metric.WithUnit("{pip}"),
+		metric.WithExplicitBucketBoundaries(1, 2, 3, 4, 5))
+	if err != nil {
+		return nil, err
+	}
+	inst.duration, err = m.Float64Histogram("dice.roll.duration",
+		metric.WithDescription("주사위를 던지고 응답을 쓰기까지 걸린 시간"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.00001, 0.00005, 0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1))
+	if err != nil {
+		return nil, err
+	}
+	inst.randErrors, err = m.Int64Counter("dice.rand.errors",
+		metric.WithDescription("난수 소스 읽기에 실패해 math/rand로 대신 던진 횟수"),
+		metric.WithUnit("{error}"))
+	if err != nil {
+		return nil, err
+	}
+	return &inst, nil
+}
+
+// anonymousPlayer는 경로에 플레이어 이름이 없을 때 player 속성에 쓰는 값입니다.
+const anonymousPlayer = "anonymous"
+
+// diceHandler는 주사위 던지기 요청을 처리합니다.
+type diceHandler struct {
+	// normalizePlayer가 켜져 있으면 플레이어 이름의 앞뒤 공백을 없애고 소문자로 바꿔
+	// "Alice", "alice", "ALICE"가 같은 속성 값이 되도록 합니다.
+	normalizePlayer bool
+
+	// baggageKeys에 있는 배기지 멤버만 주사위 메트릭 속성이 됩니다.
+	// 배기지는 호출자가 마음대로 채울 수 있으므로 허용 목록 밖의 키는 절대 기록하지 않습니다.
+	baggageKeys []string
+
+	// severityByValue가 켜져 있으면 던지기 로그의 심각도를 값에 따라 정합니다(rollSeverity 참고).
+	severityByValue bool
+
+	// rand는 주사위 값을 뽑을 난수 소스입니다. 기본값은 crypto/rand.Reader입니다.
+	rand io.Reader
+
+	// seeds가 있으면 rand 대신 던질 때마다 이 소스에서 뽑은 시드로 주사위 값을 정합니다.
+	// 시드는 roll 스팬의 dice.seed 속성으로 남아 같은 결과를 다시 만들 수 있습니다.
+	seeds *seedSource
+
+	// versusURL은 /rolldice/versus/{player}가 상대의 주사위를 받을 서비스 주소이고,
+	// client는 그 호출에 쓰는 계측된 클라이언트입니다(versus 참고).
+	versusURL string
+	client    *http.Client
+
+	// store가 있으면 던지기마다 기록을 남기고 /rolldice/history/{player}로 조회할 수 있습니다.
+	store *rollStore
+
+	inst *diceInstruments
+}
+
+// seedSource는 고정된 시드에서 던지기마다 쓸 시드를 차례로 뽑습니다. 테스트와 재현용입니다.
+//
+// 모든 요청이 하나의 난수 열을 나눠 쓰면 동시 요청의 순서에 따라 값이 달라져 한 번의
+// 던지기를 따로 재현할 수 없으므로, 던지기마다 새 시드를 뽑아 그 시드만으로 값을 정합니다.
+type seedSource struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func newSeedSource(seed int64) *seedSource {
+	return &seedSource{rng: rand.New(rand.NewSource(seed))}
+}
+
+func (s *seedSource) next() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Int63()
+}
+
+func newDiceHandler(cfg config, inst *diceInstruments, store *rollStore) *diceHandler {
+	h := &diceHandler{
+		normalizePlayer: cfg.NormalizePlayer,
+		baggageKeys:     cfg.BaggageMetricAttributes,
+		severityByValue: cfg.RollSeverityByValue,
+		rand:            cryptorand.Reader,
+		versusURL:       cfg.versusURL(),
+		// 자기 자신을 부르는 기본 설정에서만 인증서를 검증하지 않습니다.
+		client: newInstrumentedClient(cfg.VersusURL == "", cfg.ClientTracePhases),
+		store:  store,
+		inst:   inst,
+	}
+	if cfg.DiceSeed != 0 {
+		// 시드 값은 로그에 남기지 않습니다. 스팬 속성으로만 기록합니다.
+		log.Print("시드를 고정한 난수 소스로 주사위를 던집니다(테스트 전용)")
+		h.seeds = newSeedSource(cfg.DiceSeed)
 	}
+	return h
 }
 
-func rolldice(w http.ResponseWriter, r *http.Request) {
-	ctx, span := tracer.Start(r.Context(), "roll")
+func (h *diceHandler) rolldice(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	// 스팬, 메트릭, 로그가 모두 같은 속성 값을 쓰도록 한 번만 정규화합니다.
+	player, err := h.player(r)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.throw(r.Context(), start, player, func(roll int) error {
+		_, err := io.WriteString(w, strconv.Itoa(roll)+"\n")
+		return err
+	})
+}
+
+// throw는 player의 주사위를 던져 roll 스팬, 주사위 메트릭, 던지기 기록, 로그를 남기고 reply로
+// 값을 보냅니다. HTTP와 gRPC가 함께 쓰므로 두 경로의 텔레메트리가 같습니다.
+// dice.roll.duration은 start부터 reply가 끝날 때까지입니다. reply가 실패하면 로그를 남기고
+// 그 에러를 반환합니다.
+func (h *diceHandler) throw(ctx context.Context, start time.Time, player string, reply func(roll int) error) error {
+	reqCtx := ctx
+	ctx, span := startSpan(ctx, "roll")
 	defer span.End()
 
-	roll := 1 + rand.Intn(6)
+	roll := h.roll(ctx)
 
-	var msg string
-	if player := r.PathValue("player"); player != "" {
+	var (
+		msg        string
+		playerAttr attribute.KeyValue
+	)
+	if player != "" {
 		msg = fmt.Sprintf("%s님이 주사위를 던졌습니다", player)
+		playerAttr = attribute.String("player", player)
 	} else {
 		msg = "익명의 플레이어가 주사위를 던졌습니다"
+		// 빈 문자열 대신 이름 있는 값을 써서 대시보드에서 익명 던지기를 따로 거를 수 있게 합니다.
+		playerAttr = attribute.String("player", anonymousPlayer)
+	}
+
+	attrs := []attribute.KeyValue{attribute.Int("roll.value", roll), playerAttr}
+	span.SetAttributes(attrs...)
+	bagAttrs := h.baggageAttributes(ctx)
+	h.inst.rolls.Add(ctx, 1, metric.WithAttributes(attrs...), metric.WithAttributes(bagAttrs...))
+	rollStats.add(roll)
+	// 값 자체가 측정값이므로 roll.value 속성 없이 플레이어 속성만 붙입니다.
+	h.inst.rollValues.Record(ctx, int64(roll), metric.WithAttributes(playerAttr), metric.WithAttributes(bagAttrs...))
+	if h.store != nil {
+		// 기록 쿼리 스팬은 주사위 값을 뽑는 roll 스팬이 아닌 요청 스팬의 자식이 되도록
+		// 요청 컨텍스트로 남깁니다. 기록에 실패해도 던진 값은 응답합니다.
+		if err := h.store.record(reqCtx, playerAttr.Value.AsString(), roll, start); err != nil {
+			logger.ErrorContext(ctx, "던지기 기록 저장 실패", "error", err)
+		}
 	}
-	logger.InfoContext(ctx, msg, "결과", roll)
 
-	rollValueAttr := attribute.Int("roll.value", roll)
-	span.SetAttributes(rollValueAttr)
-	rollCnt.Add(ctx, 1, metric.WithAttributes(rollValueAttr))
+	replyErr := reply(roll)
+	if replyErr != nil {
+		logger.ErrorContext(ctx, "응답 쓰기 실패", "error", replyErr)
+	}
+	elapsed := time.Since(start).Seconds()
+	h.inst.duration.Record(ctx, elapsed, metric.WithAttributes(playerAttr), metric.WithAttributes(bagAttrs...))
 
-	resp := strconv.Itoa(roll) + "\n"
-	if _, err := io.WriteString(w, resp); err != nil {
-		log.Printf("쓰기 실패: %v\n", err)
+	// 로그 레코드는 스팬, 메트릭과 같은 키(player, roll.value, dice.roll.duration)를 쓰고
+	// ctx로 roll 스팬의 트레이스/스팬 ID를 함께 담으므로 백엔드에서 세 신호를 이을 수 있습니다.
+	logAttrs := []slog.Attr{
+		slog.String("player", playerAttr.Value.AsString()),
+		slog.Int("roll.value", roll),
+		slog.Float64("dice.roll.duration", elapsed),
+	}
+	level := slog.LevelInfo
+	if h.severityByValue {
+		var outcome string
+		level, outcome = rollSeverity(roll)
+		if outcome != "" {
+			logAttrs = append(logAttrs, slog.String("dice.outcome", outcome))
+		}
+	}
+	logger.LogAttrs(ctx, level, msg, logAttrs...)
+	return replyErr
+}
+
+// rollSeverity는 주사위 값에 따른 로그 심각도와 dice.outcome 값을 정합니다.
+// 심각도별 필터링과 라우팅을 눈으로 확인하기 위한 데모용 매핑입니다.
+//
+//	1    Warn  (dice.outcome="critical_miss")
+//	6    Info  (dice.outcome="critical_hit")
+//	2~5  Debug
+//
+// 기본 로그 수준(Info)에서는 2~5가 남지 않으므로 /admin/loglevel로 Debug까지 낮춰 확인합니다.
+func rollSeverity(roll int) (slog.Level, string) {
+	switch roll {
+	case 1:
+		return slog.LevelWarn, "critical_miss"
+	case 6:
+		return slog.LevelInfo, "critical_hit"
+	default:
+		return slog.LevelDebug, ""
+	}
+}
+
+// roll은 난수 소스에서 1~6 사이의 값을 뽑습니다.
+// crypto/rand 읽기는 드물게 실패하거나 막힐 수 있으므로, 실패하면 스팬과 카운터에
+// 기록하고 경고를 남긴 뒤 math/rand로 대신 던져 요청이 실패하지 않게 합니다.
+//
+// 시드를 고정한 경우 이번 던지기의 시드를 dice.seed 속성으로 남깁니다.
+// rand.New(rand.NewSource(seed))를 같은 방식으로 읽으면 같은 값이 나옵니다.
+// crypto/rand를 쓰는 경우에는 재현할 수 없으므로 속성을 남기지 않습니다.
+func (h *diceHandler) roll(ctx context.Context) int {
+	src := h.rand
+	if h.seeds != nil {
+		seed := h.seeds.next()
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Int64("dice.seed", seed))
+		src = rand.New(rand.NewSource(seed))
+	}
+	n, err := cryptorand.Int(src, big.NewInt(6))
+	if err == nil {
+		return 1 + int(n.Int64())
+	}
+	trace.SpanFromContext(ctx).RecordError(err)
+	// 서버 스팬에서도 대체 경로로 처리된 요청을 거를 수 있게 합니다.
+	addSpanAttributes(ctx, attribute.Bool("dice.rand.fallback", true))
+	h.inst.randErrors.Add(ctx, 1)
+	logger.WarnContext(ctx, "난수 소스를 읽지 못해 math/rand로 대신 던집니다", "error", err)
+	return 1 + rand.Intn(6)
+}
+
+// baggageAttributes는 요청 배기지에서 허용 목록에 있는 멤버만 메트릭 속성으로 바꿉니다.
+func (h *diceHandler) baggageAttributes(ctx context.Context) []attribute.KeyValue {
+	if len(h.baggageKeys) == 0 {
+		return nil
+	}
+	bag := baggage.FromContext(ctx)
+	var attrs []attribute.KeyValue
+	for _, key := range h.baggageKeys {
+		if m := bag.Member(key); m.Key() != "" {
+			attrs = append(attrs, attribute.String(key, m.Value()))
+		}
+	}
+	return attrs
+}
+
+// player는 경로의 플레이어 이름을 설정에 따라 정규화해 반환합니다.
+//
+// PathValue는 이미 URL 디코딩된 값이므로 "%20"은 공백, "%2F"는 "/"로 들어옵니다.
+// 제어 문자는 로그와 속성 값을 오염시킬 수 있으므로 정규화 여부와 관계없이 거절합니다.
+// 정규화가 켜져 있으면 끝의 "/"와 앞뒤 공백을 없애고, 연속된 공백을 하나로 줄인 뒤 소문자로 바꿉니다.
+func (h *diceHandler) player(r *http.Request) (string, error) {
+	return h.playerName(r.PathValue("player"))
+}
+
+// playerName은 이름 하나를 player와 같은 규칙으로 검사하고 정규화합니다. gRPC 요청의 이름에도 씁니다.
+func (h *diceHandler) playerName(player string) (string, error) {
+	if strings.ContainsFunc(player, unicode.IsControl) {
+		return "", errors.New("플레이어 이름에 제어 문자를 쓸 수 없습니다")
+	}
+	if h.normalizePlayer {
+		player = strings.TrimRight(player, "/")
+		player = strings.ToLower(strings.Join(strings.Fields(player), " "))
 	}
+	return player, nil
 }
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"go-opentelemetry-sample/testutil"
+)
+
+func TestRolldiceTelemetry(t *testing.T) {
+	tel := testTelemetry.Telemetry
+	h := newTestHandler(defaultConfig())
+	tests := []struct {
+		path       string
+		route      string
+		spanName   string
+		wantPlayer string
+	}{
+		{"/rolldice/", "/rolldice/", "GET /rolldice/", anonymousPlayer},
+		{"/rolldice/alice", "/rolldice/{player}", "GET /rolldice/{player}", "alice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resetTelemetry(t)
+			playerAttr := attribute.String("player", tt.wantPlayer)
+			before := testutil.CounterValue(t, tel.Metrics, "dice.rolls", playerAttr)
+
+			w := serve(h, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("상태 = %d, want 200", w.Code)
+			}
+			roll, err := strconv.Atoi(strings.TrimSpace(w.Body.String()))
+			if err != nil || roll < 1 || roll > 6 {
+				t.Fatalf("응답 %q가 1~6 사이의 값이 아닙니다", w.Body.String())
+			}
+
+			server := testutil.AssertSpanWithName(t, tel.Spans, tt.spanName)
+			testutil.AssertSpanAttribute(t, server, "http.route", attribute.StringValue(tt.route))
+
+			rollSpan := testutil.AssertSpanWithName(t, tel.Spans, "roll")
+			testutil.AssertSpanAttribute(t, rollSpan, "roll.value", attribute.IntValue(roll))
+			testutil.AssertSpanAttribute(t, rollSpan, "player", attribute.StringValue(tt.wantPlayer))
+			if rollSpan.Parent.SpanID() != server.SpanContext.SpanID() {
+				t.Errorf("roll 스팬의 부모가 서버 스팬이 아닙니다")
+			}
+
+			if got := testutil.CounterValue(t, tel.Metrics, "dice.rolls", playerAttr, attribute.Int("roll.value", roll)); got < 1 {
+				t.Errorf("player=%s, roll.value=%d인 dice.rolls가 없습니다", tt.wantPlayer, roll)
+			}
+			testutil.AssertCounterValue(t, tel.Metrics, "dice.rolls", before+1, playerAttr)
+		})
+	}
+}
+
+func TestRolldiceMetrics(t *testing.T) {
+	tel := testTelemetry.Telemetry
+	h := newTestHandler(defaultConfig())
+	tests := []struct {
+		path       string
+		wantPlayer string
+	}{
+		{"/rolldice/", anonymousPlayer},
+		{"/rolldice/metrics-player", "metrics-player"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			playerAttr := attribute.String("player", tt.wantPlayer)
+			beforeRolls := testutil.CounterValue(t, tel.Metrics, "dice.rolls", playerAttr)
+			beforeValues := testutil.HistogramCount(t, tel.Metrics, "dice.roll.value", playerAttr)
+
+			w := serve(h, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("상태 = %d, want 200", w.Code)
+			}
+			roll, _ := strconv.Atoi(strings.TrimSpace(w.Body.String()))
+
+			if got := testutil.CounterValue(t, tel.Metrics, "dice.rolls", playerAttr, attribute.Int("roll.value", roll)); got < 1 {
+				t.Errorf("player=%s, roll.value=%d인 dice.rolls가 없습니다", tt.wantPlayer, roll)
+			}
+			testutil.AssertCounterValue(t, tel.Metrics, "dice.rolls", beforeRolls+1, playerAttr)
+			if got := testutil.HistogramCount(t, tel.Metrics, "dice.roll.value", playerAttr) - beforeValues; got != 1 {
+				t.Errorf("dice.roll.value 기록 증가량 = %d, want 1", got)
+			}
+
+			// 히스토그램은 값 자체를 기록하므로 roll.value 속성이 없고 값은 1~6 버킷 안에 있습니다.
+			m, _ := testutil.FindMetric(testutil.Collect(t, tel.Metrics), "dice.roll.value")
+			for _, dp := range m.Data.(metricdata.Histogram[int64]).DataPoints {
+				if _, ok := dp.Attributes.Value("roll.value"); ok {
+					t.Errorf("dice.roll.value에 roll.value 속성이 있습니다: %v", dp.Attributes.ToSlice())
+				}
+				if v, ok := dp.Min.Value(); ok && v < 1 {
+					t.Errorf("dice.roll.value 최솟값 = %d", v)
+				}
+				if v, ok := dp.Max.Value(); ok && v > 6 {
+					t.Errorf("dice.roll.value 최댓값 = %d", v)
+				}
+			}
+		})
+	}
+}
+
+func TestRolldicePlayerNormalization(t *testing.T) {
+	tel := testTelemetry.Telemetry
+	tests := []struct {
+		normalize bool
+		path      string
+		want      string
+	}{
+		{false, "/rolldice/Alice", "Alice"},
+		{true, "/rolldice/Alice", "alice"},
+		{true, "/rolldice/ALICE", "alice"},
+		{true, "/rolldice/%20Bob%20", "bob"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/normalize=%v", tt.path, tt.normalize), func(t *testing.T) {
+			resetTelemetry(t)
+			cfg := defaultConfig()
+			cfg.NormalizePlayer = tt.normalize
+			playerAttr := attribute.String("player", tt.want)
+			before := testutil.CounterValue(t, tel.Metrics, "dice.rolls", playerAttr)
+
+			if w := serve(newTestHandler(cfg), httptest.NewRequest(http.MethodGet, tt.path, nil)); w.Code != http.StatusOK {
+				t.Fatalf("상태 = %d, want 200", w.Code)
+			}
+			testutil.AssertSpanAttribute(t, testutil.AssertSpanWithName(t, tel.Spans, "roll"), "player", attribute.StringValue(tt.want))
+			testutil.AssertCounterValue(t, tel.Metrics, "dice.rolls", before+1, playerAttr)
+		})
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("entropy unavailable") }
+
+func TestRollRandSourceFallback(t *testing.T) {
+	tel := testTelemetry.Telemetry
+	tests := []struct {
+		name         string
+		src          io.Reader
+		wantFallback bool
+	}{
+		// 0으로 채운 입력에서는 항상 1이 나옵니다.
+		{"healthy", bytes.NewReader(make([]byte, 64)), false},
+		{"read error", failingReader{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetTelemetry(t)
+			h := newDiceHandler(defaultConfig(), testDiceInstruments(), nil)
+			h.rand = tt.src
+			before := testutil.CounterValue(t, tel.Metrics, "dice.rand.errors")
+
+			// 서버 스팬과 속성 주머니를 직접 만들어 대체 경로 속성이 서버 스팬에 붙는지 봅니다.
+			ctx, server := tracer.Start(context.Background(), "server")
+			r := httptest.NewRequest(http.MethodGet, "/rolldice/", nil).WithContext(ctx)
+			w := serve(withAttributeBag(http.HandlerFunc(h.rolldice)), r)
+			server.End()
+			if w.Code != http.StatusOK {
+				t.Fatalf("상태 = %d, want 200", w.Code)
+			}
+			if !tt.wantFallback && w.Body.String() != "1\n" {
+				t.Errorf("응답 = %q, want \"1\\n\"", w.Body.String())
+			}
+
+			_, fallback := testutil.SpanAttribute(testutil.AssertSpanWithName(t, tel.Spans, "server"), "dice.rand.fallback")
+			if fallback != tt.wantFallback {
+				t.Errorf("서버 스팬의 dice.rand.fallback 있음 = %v, want %v", fallback, tt.wantFallback)
+			}
+			var exceptions int
+			for _, ev := range testutil.AssertSpanWithName(t, tel.Spans, "roll").Events {
+				if ev.Name == "exception" {
+					exceptions++
+				}
+			}
+			if got := exceptions == 1; got != tt.wantFallback {
+				t.Errorf("roll 스팬의 exception 이벤트 %d개", exceptions)
+			}
+			wantErrors := int64(0)
+			if tt.wantFallback {
+				wantErrors = 1
+			}
+			testutil.AssertCounterValue(t, tel.Metrics, "dice.rand.errors", before+wantErrors)
+		})
+	}
+}
+
+func TestRolldicePlayerEncoding(t *testing.T) {
+	tests := []struct {
+		normalize  bool
+		path       string
+		wantStatus int
+		wantPlayer string
+	}{
+		{true, "/rolldice/al%20%20%20ice%20", http.StatusOK, "al ice"},
+		{true, "/rolldice/bob%2F", http.StatusOK, "bob"},
+		{true, "/rolldice/%E3%80%80carol", http.StatusOK, "carol"},
+		{true, "/rolldice/%09carol", http.StatusBadRequest, ""},
+		{false, "/rolldice/dave%20", http.StatusOK, "dave "},
+		{true, "/rolldice/eve%0Alog", http.StatusBadRequest, ""},
+		{false, "/rolldice/eve%00", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/normalize=%v", tt.path, tt.normalize), func(t *testing.T) {
+			resetTelemetry(t)
+			cfg := defaultConfig()
+			cfg.NormalizePlayer = tt.normalize
+			w := serve(newTestHandler(cfg), httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("상태 = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+					t.Errorf("Content-Type = %q, want application/problem+json", ct)
+				}
+				// 거절한 요청은 주사위를 던지지 않습니다.
+				for _, s := range endedSpans() {
+					if s.Name == "roll" {
+						t.Error("roll 스팬이 만들어졌습니다")
+					}
+				}
+				return
+			}
+			assertSpanAttr(t, findSpan(t, "roll"), "player", attribute.StringValue(tt.wantPlayer))
+		})
+	}
+}
+
+func TestDiceInstrumentsShared(t *testing.T) {
+	// 핸들러를 여러 개 만들어도 계측기를 한 번만 만들어 나눠 쓰므로 메트릭 스트림이 하나씩입니다.
+	tel := testutil.New()
+	mp := sdkmetric.NewMeterProvider(tel.MeterProviderOption())
+	defer mp.Shutdown(context.Background())
+	inst, err := newDiceInstruments(mp.Meter(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		h := newDiceHandler(defaultConfig(), inst, nil)
+		serve(http.HandlerFunc(h.rolldice), httptest.NewRequest(http.MethodGet, "/rolldice/", nil))
+	}
+
+	rm := testutil.Collect(t, tel.Metrics)
+	streams := map[string]int{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			streams[m.Name]++
+		}
+	}
+	for _, metricName := range []string{"dice.rolls", "dice.roll.value", "dice.roll.duration"} {
+		if streams[metricName] != 1 {
+			t.Errorf("%s 스트림 %d개, want 1", metricName, streams[metricName])
+		}
+	}
+	testutil.AssertCounterValue(t, tel.Metrics, "dice.rolls", 2)
+}
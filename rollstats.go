@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// rollStats는 시작 이후의 누적 던지기 통계입니다. dice.rolls 카운터를 올리는 자리에서
+// 함께 올리므로 카운터 합계와 같은 값이 되며, 메트릭 백엔드 없이 /admin/stats로 볼 수 있습니다.
+var rollStats = newRollCounts()
+
+// rollCounts는 면별 던지기 횟수를 원자적으로 셉니다.
+type rollCounts struct {
+	startedAt time.Time
+	faces     [6]atomic.Int64
+}
+
+func newRollCounts() *rollCounts {
+	return &rollCounts{startedAt: time.Now()}
+}
+
+// add는 roll(1~6) 한 번을 셉니다. 범위 밖의 값은 무시합니다.
+func (c *rollCounts) add(roll int) {
+	if roll >= 1 && roll <= len(c.faces) {
+		c.faces[roll-1].Add(1)
+	}
+}
+
+// rollStatsResponse는 /admin/stats의 응답 본문입니다.
+type rollStatsResponse struct {
+	TotalRolls    int64            `json:"total_rolls"`
+	Faces         map[string]int64 `json:"faces"`
+	UptimeSeconds float64          `json:"uptime_seconds"`
+}
+
+// serveRollStats는 누적 던지기 횟수, 면별 횟수, 가동 시간을 반환합니다.
+// 면별 값은 하나씩 읽으므로 동시에 던지는 중이면 합계가 순간적으로 한두 개 어긋날 수 있습니다.
+func serveRollStats(w http.ResponseWriter, _ *http.Request) {
+	resp := rollStatsResponse{
+		Faces:         make(map[string]int64, len(rollStats.faces)),
+		UptimeSeconds: time.Since(rollStats.startedAt).Seconds(),
+	}
+	for i := range rollStats.faces {
+		n := rollStats.faces[i].Load()
+		resp.Faces[strconv.Itoa(i+1)] = n
+		resp.TotalRolls += n
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/XSAM/otelsql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	_ "modernc.org/sqlite"
+)
+
+// historyLimit은 /rolldice/history/{player}가 돌려주는 최대 기록 수입니다.
+const historyLimit = 100
+
+// rollSchema는 rolls 테이블과 플레이어별 조회용 인덱스입니다. SQLite와 Postgres에서 모두 동작합니다.
+var rollSchema = []string{
+	`CREATE TABLE IF NOT EXISTS rolls (
+		player    TEXT      NOT NULL,
+		value     INTEGER   NOT NULL,
+		rolled_at TIMESTAMP NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS rolls_player_rolled_at ON rolls (player, rolled_at)`,
+}
+
+// rollStore는 주사위 던지기 기록(플레이어, 값, 시각)을 데이터베이스에 남기고 조회합니다.
+//
+// 모든 쿼리는 otelsql로 감싼 드라이버를 거치므로, 요청 컨텍스트를 넘기면 쿼리 스팬이
+// 그 요청의 스팬 아래에 기록됩니다. 연결 풀 상태(sql.DBStats)는 db.sql.connection.*
+// 메트릭으로, 호출 지연은 db.sql.latency 히스토그램으로 나갑니다.
+type rollStore struct {
+	db *sql.DB
+
+	// insertQuery와 historyQuery는 드라이버의 자리 표시자 문법("?" 또는 "$1")으로 쓴 쿼리입니다.
+	insertQuery  string
+	historyQuery string
+}
+
+// rollRecord는 던지기 기록 하나입니다.
+type rollRecord struct {
+	Value    int       `json:"value"`
+	RolledAt time.Time `json:"rolled_at"`
+}
+
+// openRollStore는 dsn의 데이터베이스를 열고 rolls 테이블을 준비합니다.
+// dsn이 "postgres://" 또는 "postgresql://"로 시작하면 Postgres(pgx)를, 그 밖에는
+// SQLite 파일 경로(또는 ":memory:")로 보고 modernc.org/sqlite를 씁니다.
+// 스팬과 메트릭은 전역 제공자로 나가므로 setupOTelSDK 다음에 불러야 합니다.
+func openRollStore(ctx context.Context, dsn string) (*rollStore, error) {
+	driverName, system := "sqlite", semconv.DBSystemSqlite
+	s := &rollStore{
+		insertQuery:  "INSERT INTO rolls (player, value, rolled_at) VALUES (?, ?, ?)",
+		historyQuery: "SELECT value, rolled_at FROM rolls WHERE player = ? ORDER BY rolled_at DESC LIMIT ?",
+	}
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		driverName, system = "pgx", semconv.DBSystemPostgreSQL
+		s.insertQuery = "INSERT INTO rolls (player, value, rolled_at) VALUES ($1, $2, $3)"
+		s.historyQuery = "SELECT value, rolled_at FROM rolls WHERE player = $1 ORDER BY rolled_at DESC LIMIT $2"
+	}
+
+	// db.system 속성은 쿼리 스팬과 연결 풀 메트릭에 함께 붙습니다.
+	// 풀에서 연결을 꺼낼 때마다 생기는 sql.conn.reset_session 스팬은 쿼리마다 하나씩 늘어
+	// 트레이스만 어지럽히므로 만들지 않습니다.
+	attrs := otelsql.WithAttributes(system)
+	db, err := otelsql.Open(driverName, dsn, attrs,
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true}))
+	if err != nil {
+		return nil, fmt.Errorf("데이터베이스 열기: %w", err)
+	}
+	if driverName == "sqlite" {
+		// SQLite는 한 번에 한 연결만 쓸 수 있고 ":memory:" 데이터베이스는 연결마다 따로 생기므로
+		// 연결 하나를 나눠 씁니다.
+		db.SetMaxOpenConns(1)
+	}
+	for _, stmt := range rollSchema {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, errors.Join(fmt.Errorf("rolls 테이블 준비: %w", err), db.Close())
+		}
+	}
+	if err := otelsql.RegisterDBStatsMetrics(db, attrs); err != nil {
+		return nil, errors.Join(fmt.Errorf("DB 통계 메트릭 등록: %w", err), db.Close())
+	}
+	s.db = db
+	return s, nil
+}
+
+// record는 player가 at에 value를 던졌다고 기록합니다.
+func (s *rollStore) record(ctx context.Context, player string, value int, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, s.insertQuery, player, value, at.UTC())
+	return err
+}
+
+// history는 player의 최근 기록을 최신순으로 최대 limit개 반환합니다.
+func (s *rollStore) history(ctx context.Context, player string, limit int) ([]rollRecord, error) {
+	rows, err := s.db.QueryContext(ctx, s.historyQuery, player, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	records := []rollRecord{}
+	for rows.Next() {
+		var rec rollRecord
+		if err := rows.Scan(&rec.Value, &rec.RolledAt); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+// Close는 데이터베이스 연결을 닫습니다. nil이면 아무 일도 하지 않습니다.
+func (s *rollStore) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// rollHistoryResponse는 /rolldice/history/{player}의 응답 본문입니다.
+type rollHistoryResponse struct {
+	Player string       `json:"player"`
+	Rolls  []rollRecord `json:"rolls"`
+}
+
+// history는 플레이어의 최근 던지기 기록을 최신순으로 반환합니다.
+// /rolldice/{player}와 같은 방식으로 이름을 정규화하므로 기록할 때와 같은 키로 찾습니다.
+func (h *diceHandler) history(w http.ResponseWriter, r *http.Request) {
+	player, err := h.player(r)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if player == "" {
+		player = anonymousPlayer
+	}
+	records, err := h.store.history(r.Context(), player, historyLimit)
+	if err != nil {
+		logger.ErrorContext(r.Context(), "던지기 기록 조회 실패", "player", player, "error", err)
+		writeProblem(w, r, http.StatusInternalServerError, "던지기 기록을 읽지 못했습니다")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rollHistoryResponse{Player: player, Rolls: records})
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRollHistory(t *testing.T) {
+	h := newTestHandler(defaultConfig())
+	// 저장소는 테스트가 함께 쓰므로 실행마다 새 플레이어로 기록합니다.
+	player := "history-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	var want []int
+	for range 3 {
+		w := serve(h, httptest.NewRequest(http.MethodGet, "/rolldice/"+player, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("상태 = %d, want 200", w.Code)
+		}
+		roll, _ := strconv.Atoi(strings.TrimSpace(w.Body.String()))
+		want = append([]int{roll}, want...)
+	}
+
+	w := serve(h, httptest.NewRequest(http.MethodGet, "/rolldice/history/"+player, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("상태 = %d, want 200", w.Code)
+	}
+	var resp rollHistoryResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Player != player || len(resp.Rolls) != len(want) {
+		t.Fatalf("응답 = %+v, want %s의 기록 %d개", resp, player, len(want))
+	}
+	// 최신순이므로 마지막에 던진 값이 먼저 나옵니다.
+	for i, rec := range resp.Rolls {
+		if rec.Value != want[i] {
+			t.Errorf("rolls[%d].value = %d, want %d", i, rec.Value, want[i])
+		}
+		if i > 0 && rec.RolledAt.After(resp.Rolls[i-1].RolledAt) {
+			t.Errorf("rolls[%d]가 앞의 기록보다 나중입니다", i)
+		}
+	}
+}
+
+func TestRollStoreTelemetry(t *testing.T) {
+	h := newTestHandler(defaultConfig())
+	tests := []struct {
+		path     string
+		spanName string
+		dbSpan   string
+		keyword  string
+	}{
+		{"/rolldice/db-player", "GET /rolldice/{player}", "sql.conn.exec", "INSERT"},
+		{"/rolldice/history/db-player", "GET /rolldice/history/{player}", "sql.conn.query", "SELECT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resetTelemetry(t)
+			if w := serve(h, httptest.NewRequest(http.MethodGet, tt.path, nil)); w.Code != http.StatusOK {
+				t.Fatalf("상태 = %d, want 200", w.Code)
+			}
+
+			// 쿼리 스팬은 요청 스팬의 자식입니다.
+			server := findSpan(t, tt.spanName)
+			query := findSpan(t, tt.dbSpan)
+			if query.Parent.SpanID() != server.SpanContext.SpanID() {
+				t.Errorf("%s 스팬의 부모가 서버 스팬이 아닙니다", tt.dbSpan)
+			}
+			assertSpanAttr(t, query, "db.system", attribute.StringValue("sqlite"))
+			if stmt, _ := spanAttr(query, "db.statement"); !strings.HasPrefix(stmt.AsString(), tt.keyword) {
+				t.Errorf("db.statement = %q, want %s 쿼리", stmt.AsString(), tt.keyword)
+			}
+		})
+	}
+
+	// 연결 풀 상태는 db.system 속성과 함께 메트릭으로 나갑니다.
+	m, ok := findMetric(collectMetrics(t), "db.sql.connection.open")
+	if !ok {
+		t.Fatal("db.sql.connection.open 메트릭이 없습니다")
+	}
+	var found bool
+	for _, dp := range m.Data.(metricdata.Gauge[int64]).DataPoints {
+		if v, ok := dp.Attributes.Value("db.system"); ok && v.AsString() == "sqlite" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("db.system=sqlite인 db.sql.connection.open 데이터 포인트가 없습니다")
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestRouteMetricAttributes(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Bench = true
+	h := newTestHandler(cfg)
+	feature := attribute.String("app.feature", "bench")
+	tests := []struct {
+		path        string
+		route       string
+		wantFeature bool
+	}{
+		{"/rolldice/bench", "/rolldice/bench", true},
+		// 속성을 지정하지 않은 경로의 메트릭에는 붙지 않습니다.
+		{"/rolldice/alice", "/rolldice/{player}", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resetTelemetry(t)
+			routeAttr := attribute.String("http.route", tt.route)
+			before := histogramCount(t, "http.server.duration", routeAttr)
+			beforeFeature := histogramCount(t, "http.server.duration", routeAttr, feature)
+
+			if w := serve(h, httptest.NewRequest(http.MethodGet, tt.path, nil)); w.Code != http.StatusOK {
+				t.Fatalf("상태 = %d, want 200", w.Code)
+			}
+			if got := histogramCount(t, "http.server.duration", routeAttr) - before; got != 1 {
+				t.Errorf("http.route=%s인 http.server.duration 기록 증가량 = %d, want 1", tt.route, got)
+			}
+			wantFeature := uint64(0)
+			if tt.wantFeature {
+				wantFeature = 1
+			}
+			if got := histogramCount(t, "http.server.duration", routeAttr, feature) - beforeFeature; got != wantFeature {
+				t.Errorf("app.feature=bench인 기록 증가량 = %d, want %d", got, wantFeature)
+			}
+			// 메트릭 전용 속성이므로 서버 스팬에는 붙지 않습니다.
+			if _, ok := spanAttr(findSpan(t, "GET "+tt.route), "app.feature"); ok {
+				t.Error("서버 스팬에 app.feature가 붙었습니다")
+			}
+		})
+	}
+}
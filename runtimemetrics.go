@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Go 런타임 메트릭입니다. 이름은 OpenTelemetry Go 런타임 시맨틱 규약을 따르고, 규약에 없는
+// GC 횟수와 누적 정지 시간은 go.gc.* 아래에 둡니다. 다른 메트릭과 같은 제공자로 기록되므로
+// OTLP와 /metrics(dice_game_ 접두사)에 함께 나타납니다.
+//
+// 호스트 CPU와 메모리 메트릭은 없습니다. 그 값을 읽는 contrib runtime, host 계측 모듈이
+// 이 트리에서 쓸 수 있는 모듈에 없어, 표준 라이브러리로 읽을 수 있는 런타임 값만 직접 기록합니다.
+var (
+	runtimeGoroutines  metric.Int64ObservableUpDownCounter
+	runtimeMemoryUsed  metric.Int64ObservableUpDownCounter
+	runtimeHeapAlloc   metric.Int64ObservableCounter
+	runtimeAllocations metric.Int64ObservableCounter
+	runtimeGCGoal      metric.Int64ObservableUpDownCounter
+	runtimeGCCount     metric.Int64ObservableCounter
+	runtimeGCPause     metric.Float64ObservableCounter
+	runtimeProcessors  metric.Int64ObservableUpDownCounter
+)
+
+func init() {
+	var err error
+	runtimeGoroutines, err = meter.Int64ObservableUpDownCounter("go.goroutine.count",
+		metric.WithDescription("살아 있는 고루틴 수"),
+		metric.WithUnit("{goroutine}"))
+	if err != nil {
+		panic(err)
+	}
+	runtimeMemoryUsed, err = meter.Int64ObservableUpDownCounter("go.memory.used",
+		metric.WithDescription("Go 런타임이 OS에서 받아 쓰고 있는 메모리(반환한 힙 제외)"),
+		metric.WithUnit("By"))
+	if err != nil {
+		panic(err)
+	}
+	runtimeHeapAlloc, err = meter.Int64ObservableCounter("go.memory.allocated",
+		metric.WithDescription("힙에 할당한 누적 바이트 수"),
+		metric.WithUnit("By"))
+	if err != nil {
+		panic(err)
+	}
+	runtimeAllocations, err = meter.Int64ObservableCounter("go.memory.allocations",
+		metric.WithDescription("힙에 할당한 누적 객체 수"),
+		metric.WithUnit("{allocation}"))
+	if err != nil {
+		panic(err)
+	}
+	runtimeGCGoal, err = meter.Int64ObservableUpDownCounter("go.memory.gc.goal",
+		metric.WithDescription("다음 GC가 시작될 힙 크기"),
+		metric.WithUnit("By"))
+	if err != nil {
+		panic(err)
+	}
+	runtimeGCCount, err = meter.Int64ObservableCounter("go.gc.count",
+		metric.WithDescription("완료된 GC 주기 수"),
+		metric.WithUnit("{gc_cycle}"))
+	if err != nil {
+		panic(err)
+	}
+	runtimeGCPause, err = meter.Float64ObservableCounter("go.gc.pause.time",
+		metric.WithDescription("GC로 프로그램이 멈춘 누적 시간"),
+		metric.WithUnit("s"))
+	if err != nil {
+		panic(err)
+	}
+	runtimeProcessors, err = meter.Int64ObservableUpDownCounter("go.processor.limit",
+		metric.WithDescription("동시에 Go 코드를 실행할 수 있는 OS 스레드 수(GOMAXPROCS)"),
+		metric.WithUnit("{thread}"))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// memStatsCache는 runtime.ReadMemStats 결과를 minInterval 동안 재사용합니다.
+// ReadMemStats는 잠깐 프로그램 전체를 멈추므로, 리더가 여럿이거나(주기적 내보내기와
+// Prometheus 스크레이프) 스크레이프가 잦아도 그보다 자주 읽지 않습니다.
+type memStatsCache struct {
+	minInterval time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+	ms   runtime.MemStats
+}
+
+func (c *memStatsCache) read() runtime.MemStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now := time.Now(); c.last.IsZero() || now.Sub(c.last) >= c.minInterval {
+		runtime.ReadMemStats(&c.ms)
+		c.last = now
+	}
+	return c.ms
+}
+
+// registerRuntimeMetrics는 수집할 때마다 런타임 메트릭을 관찰하는 콜백을 등록합니다.
+// 메모리 통계는 최대 minInterval마다 새로 읽습니다. 반환한 함수는 콜백 등록을 해제합니다.
+func registerRuntimeMetrics(minInterval time.Duration) (func(context.Context) error, error) {
+	cache := &memStatsCache{minInterval: minInterval}
+	reg, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		ms := cache.read()
+		o.ObserveInt64(runtimeGoroutines, int64(runtime.NumGoroutine()))
+		o.ObserveInt64(runtimeMemoryUsed, int64(ms.Sys-ms.HeapReleased))
+		o.ObserveInt64(runtimeHeapAlloc, int64(ms.TotalAlloc))
+		o.ObserveInt64(runtimeAllocations, int64(ms.Mallocs))
+		o.ObserveInt64(runtimeGCGoal, int64(ms.NextGC))
+		o.ObserveInt64(runtimeGCCount, int64(ms.NumGC))
+		o.ObserveFloat64(runtimeGCPause, time.Duration(ms.PauseTotalNs).Seconds())
+		o.ObserveInt64(runtimeProcessors, int64(runtime.GOMAXPROCS(0)))
+		return nil
+	}, runtimeGoroutines, runtimeMemoryUsed, runtimeHeapAlloc, runtimeAllocations,
+		runtimeGCGoal, runtimeGCCount, runtimeGCPause, runtimeProcessors)
+	if err != nil {
+		return nil, err
+	}
+	return func(context.Context) error { return reg.Unregister() }, nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// samplerFactory는 설정으로 표본 추출기를 만듭니다. 인자는 보통 cfg.TracesSamplerArg에서 읽습니다.
+type samplerFactory func(cfg config) (trace.Sampler, error)
+
+// samplers는 OTEL_TRACES_SAMPLER로 고를 수 있는 표본 추출기입니다.
+//
+// 직접 만든 표본 추출기를 쓰려면 이 패키지에 파일을 하나 더해 init에서 등록합니다.
+//
+//	func init() {
+//		registerSampler("errors_only", func(cfg config) (trace.Sampler, error) {
+//			return newErrorsOnlySampler(), nil
+//		})
+//	}
+//
+// 그러면 OTEL_TRACES_SAMPLER=errors_only로 고를 수 있고, 잘못된 이름은 시작할 때 에러가 됩니다.
+var samplers = map[string]samplerFactory{}
+
+// registerSampler는 name으로 표본 추출기를 등록합니다. 같은 이름을 두 번 등록하면 패닉입니다.
+// 패키지 초기화 중에만 호출해야 합니다.
+func registerSampler(name string, f samplerFactory) {
+	if _, dup := samplers[name]; dup {
+		panic(fmt.Sprintf("표본 추출기 %q가 이미 등록되어 있습니다", name))
+	}
+	samplers[name] = f
+}
+
+func init() {
+	registerSampler("always_on", func(config) (trace.Sampler, error) { return trace.AlwaysSample(), nil })
+	registerSampler("always_off", func(config) (trace.Sampler, error) { return trace.NeverSample(), nil })
+	registerSampler("traceidratio", ratioSampler)
+	registerSampler("ratelimited", rateLimitedSampler)
+	// 표준 이름을 따라 부모가 있으면 부모의 결정을 따르는 "parentbased_" 변형을 함께 등록합니다.
+	for _, name := range []string{"always_on", "always_off", "traceidratio", "ratelimited"} {
+		root := samplers[name]
+		registerSampler("parentbased_"+name, func(cfg config) (trace.Sampler, error) {
+			s, err := root(cfg)
+			if err != nil {
+				return nil, err
+			}
+			return trace.ParentBased(s), nil
+		})
+	}
+}
+
+// newSampler는 OTEL_TRACES_SAMPLER와 OTEL_TRACES_SAMPLER_ARG로 표본 추출기를 만듭니다.
+// SDK도 같은 환경 변수를 읽지만 잘못된 값이면 경고만 남기고 기본값을 쓰므로,
+// 여기서 직접 만들어 잘못된 설정이 시작할 때 에러가 되게 합니다.
+func newSampler(cfg config) (trace.Sampler, error) {
+	f, ok := samplers[cfg.TracesSampler]
+	if !ok {
+		return nil, fmt.Errorf("OTEL_TRACES_SAMPLER: 알 수 없는 표본 추출기 %q(가능한 값: %v)", cfg.TracesSampler, samplerNames())
+	}
+	return f(cfg)
+}
+
+func samplerNames() []string {
+	names := make([]string, 0, len(samplers))
+	for name := range samplers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func ratioSampler(cfg config) (trace.Sampler, error) {
+	ratio, err := cfg.samplerRatio()
+	if err != nil {
+		return nil, err
+	}
+	return trace.TraceIDRatioBased(ratio), nil
+}
+
+// defaultSamplerRate는 OTEL_TRACES_SAMPLER_ARG가 없을 때 ratelimited가 쓰는 초당 트레이스 수입니다.
+const defaultSamplerRate = 10
+
+func rateLimitedSampler(cfg config) (trace.Sampler, error) {
+	rate := float64(defaultSamplerRate)
+	if cfg.TracesSamplerArg != "" {
+		var err error
+		rate, err = strconv.ParseFloat(cfg.TracesSamplerArg, 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("OTEL_TRACES_SAMPLER_ARG: ratelimited는 0보다 큰 초당 트레이스 수가 필요합니다: %q", cfg.TracesSamplerArg)
+		}
+	}
+	return newRateLimitingSampler(rate), nil
+}
+
+// rateLimitingSampler는 초당 최대 rate개까지 표본 추출하는 토큰 버킷입니다.
+// 버킷 크기는 rate(최소 1)라서 1초 분량까지 몰아서 받을 수 있습니다.
+type rateLimitingSampler struct {
+	rate float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimitingSampler(rate float64) *rateLimitingSampler {
+	return &rateLimitingSampler{rate: rate, tokens: max(rate, 1), last: time.Now()}
+}
+
+func (s *rateLimitingSampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	s.mu.Lock()
+	now := time.Now()
+	s.tokens = min(max(s.rate, 1), s.tokens+now.Sub(s.last).Seconds()*s.rate)
+	s.last = now
+	ok := s.tokens >= 1
+	if ok {
+		s.tokens--
+	}
+	s.mu.Unlock()
+
+	ts := oteltrace.SpanContextFromContext(p.ParentContext).TraceState()
+	if !ok {
+		return trace.SamplingResult{Decision: trace.Drop, Tracestate: ts}
+	}
+	return trace.SamplingResult{Decision: trace.RecordAndSample, Tracestate: ts}
+}
+
+func (s *rateLimitingSampler) Description() string {
+	return fmt.Sprintf("RateLimitingSampler{%g}", s.rate)
+}
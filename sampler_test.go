@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// 문서에 적은 방법 그대로 테스트 전용 표본 추출기를 등록합니다.
+func init() {
+	registerSampler("test_attribute", func(cfg config) (sdktrace.Sampler, error) {
+		return attributeSampler{key: attribute.Key(cfg.TracesSamplerArg)}, nil
+	})
+}
+
+// attributeSampler는 시작할 때 key 속성이 true인 스팬만 표본 추출합니다.
+type attributeSampler struct {
+	key attribute.Key
+}
+
+func (s attributeSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, kv := range p.Attributes {
+		if kv.Key == s.key && kv.Value.AsBool() {
+			return sdktrace.SamplingResult{Decision: sdktrace.RecordAndSample}
+		}
+	}
+	return sdktrace.SamplingResult{Decision: sdktrace.Drop}
+}
+
+func (s attributeSampler) Description() string { return "AttributeSampler{" + string(s.key) + "}" }
+
+func TestTraceSamplerConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		sampler     string
+		arg         string
+		wantSampled bool
+		wantErr     string
+	}{
+		{name: "default samples roots", wantSampled: true},
+		{name: "ratio zero drops", sampler: "traceidratio", arg: "0.0", wantSampled: false},
+		{name: "parent based ratio one", sampler: "parentbased_traceidratio", arg: "1", wantSampled: true},
+		{name: "ratio above one", sampler: "traceidratio", arg: "2.0", wantErr: "OTEL_TRACES_SAMPLER_ARG: 0~1 범위여야 합니다: 2"},
+		{name: "ratio not a number", sampler: "parentbased_traceidratio", arg: "half", wantErr: `OTEL_TRACES_SAMPLER_ARG: 숫자가 아닙니다: "half"`},
+		{name: "unknown sampler", sampler: "sometimes", wantErr: `OTEL_TRACES_SAMPLER: 알 수 없는 표본 추출기 "sometimes"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.sampler != "" {
+				t.Setenv("OTEL_TRACES_SAMPLER", tt.sampler)
+			}
+			if tt.arg != "" {
+				t.Setenv("OTEL_TRACES_SAMPLER_ARG", tt.arg)
+			}
+			cfg, err := loadConfig(nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("loadConfig 에러 = %v, want %q 포함", err, tt.wantErr)
+				}
+				// loadConfig를 거치지 않은 설정도 제공자를 만들 때 패닉 없이 에러가 됩니다.
+				cfg = defaultConfig()
+				cfg.TracesSampler, cfg.TracesSamplerArg = tt.sampler, tt.arg
+				if _, err := newTraceProvider(context.Background(), cfg, resource.Empty(), tracetest.NewInMemoryExporter()); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("newTraceProvider 에러 = %v, want %q 포함", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadConfig: %v", err)
+			}
+			cfg.TracesProcessor = "simple"
+
+			exp := tracetest.NewInMemoryExporter()
+			tp, err := newTraceProvider(context.Background(), cfg, resource.Empty(), exp)
+			if err != nil {
+				t.Fatalf("newTraceProvider: %v", err)
+			}
+			defer tp.Shutdown(context.Background())
+
+			_, span := tp.Tracer("test").Start(context.Background(), "root")
+			span.End()
+			if got := span.SpanContext().IsSampled(); got != tt.wantSampled {
+				t.Errorf("표본 추출 = %v, want %v", got, tt.wantSampled)
+			}
+			wantExported := 0
+			if tt.wantSampled {
+				wantExported = 1
+			}
+			if got := len(exp.GetSpans()); got != wantExported {
+				t.Errorf("내보낸 스팬 %d개, want %d", got, wantExported)
+			}
+		})
+	}
+}
+
+func TestCustomSampler(t *testing.T) {
+	t.Setenv("OTEL_TRACES_SAMPLER", "test_attribute")
+	t.Setenv("OTEL_TRACES_SAMPLER_ARG", "keep")
+	cfg, err := loadConfig(nil)
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	sampler, err := newSampler(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp, exp := newRecordingTracerProvider(t, nil, sdktrace.WithSampler(sampler))
+	tests := []struct {
+		name string
+		keep bool
+	}{
+		{"kept", true},
+		{"dropped", false},
+	}
+	for _, tt := range tests {
+		_, span := tp.Tracer("test").Start(context.Background(), tt.name, oteltrace.WithAttributes(attribute.Bool("keep", tt.keep)))
+		span.End()
+	}
+	if spans := exp.GetSpans(); len(spans) != 1 || spans[0].Name != "kept" {
+		t.Errorf("내보낸 스팬 = %v, want [kept]", spans)
+	}
+}
+
+func TestSamplerRegistry(t *testing.T) {
+	// 등록한 이름은 잘못된 이름의 에러 메시지에도 나옵니다.
+	cfg := defaultConfig()
+	cfg.TracesSampler = "nope"
+	if _, err := newSampler(cfg); err == nil || !strings.Contains(err.Error(), "test_attribute") {
+		t.Errorf("에러 = %v, want 등록된 이름 포함", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("같은 이름을 두 번 등록했는데 패닉이 없습니다")
+		}
+	}()
+	registerSampler("always_on", func(config) (sdktrace.Sampler, error) { return sdktrace.NeverSample(), nil })
+}
+
+func TestRateLimitingSampler(t *testing.T) {
+	s := newRateLimitingSampler(2)
+	sampled := func() bool {
+		return s.ShouldSample(sdktrace.SamplingParameters{ParentContext: context.Background()}).Decision == sdktrace.RecordAndSample
+	}
+	for i, want := range []bool{true, true, false} {
+		if got := sampled(); got != want {
+			t.Errorf("%d번째 = %v, want %v", i, got, want)
+		}
+	}
+	// 1초가 지나면 버킷이 다시 찹니다.
+	s.mu.Lock()
+	s.last = s.last.Add(-time.Second)
+	s.mu.Unlock()
+	for i, want := range []bool{true, true, false} {
+		if got := sampled(); got != want {
+			t.Errorf("1초 뒤 %d번째 = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestRateLimitedSamplerConfig(t *testing.T) {
+	tests := []struct {
+		arg     string
+		wantErr bool
+	}{
+		{"", false},
+		{"0.5", false},
+		{"0", true},
+		{"fast", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.TracesSampler = "parentbased_ratelimited"
+			cfg.TracesSamplerArg = tt.arg
+			_, err := newSampler(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("에러 = %v, want 에러 %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fixedIDGenerator는 항상 같은 트레이스 ID로 새 루트 스팬을 만듭니다.
+type fixedIDGenerator struct {
+	traceID oteltrace.TraceID
+}
+
+func (g fixedIDGenerator) NewIDs(context.Context) (oteltrace.TraceID, oteltrace.SpanID) {
+	return g.traceID, oteltrace.SpanID{1}
+}
+
+func (g fixedIDGenerator) NewSpanID(context.Context, oteltrace.TraceID) oteltrace.SpanID {
+	return oteltrace.SpanID{2}
+}
+
+func TestSamplerModes(t *testing.T) {
+	// TraceIDRatioBased는 트레이스 ID 뒤쪽 8바이트로 정하므로, 0으로 채운 ID는 비율이 0보다 크면
+	// 항상 뽑히고 0xff로 채운 ID는 비율이 1보다 작으면 항상 빠집니다.
+	low := oteltrace.TraceID{0xab}
+	high := oteltrace.TraceID{0xab, 8: 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	const (
+		root            = "root"
+		sampledParent   = "sampled parent"
+		unsampledParent = "unsampled parent"
+	)
+	tests := []struct {
+		sampler string
+		arg     string
+		traceID oteltrace.TraceID
+		parent  string
+		want    bool
+	}{
+		{"always_on", "", high, root, true},
+		{"always_on", "", high, unsampledParent, true},
+		{"always_off", "", low, root, false},
+		{"always_off", "", low, sampledParent, false},
+		{"traceidratio", "0.5", low, root, true},
+		{"traceidratio", "0.5", high, root, false},
+		// 부모를 보지 않으므로 뽑힌 부모 아래라도 빠질 수 있습니다.
+		{"traceidratio", "0.5", high, sampledParent, false},
+		{"parentbased_traceidratio", "0.5", low, root, true},
+		{"parentbased_traceidratio", "0.5", high, root, false},
+		{"parentbased_traceidratio", "0.5", high, sampledParent, true},
+		{"parentbased_traceidratio", "0.5", low, unsampledParent, false},
+		{"parentbased_always_on", "", high, root, true},
+		{"parentbased_always_on", "", high, unsampledParent, false},
+	}
+	for _, tt := range tests {
+		name := fmt.Sprintf("%s(%s)/%s/%s", tt.sampler, tt.arg, tt.traceID, tt.parent)
+		t.Run(name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.TracesSampler, cfg.TracesSamplerArg = tt.sampler, tt.arg
+			cfg.TracesProcessor = "simple"
+			exp := tracetest.NewInMemoryExporter()
+			tp, err := newTraceProvider(context.Background(), cfg, resource.Empty(), exp,
+				sdktrace.WithIDGenerator(fixedIDGenerator{tt.traceID}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer tp.Shutdown(context.Background())
+
+			ctx := context.Background()
+			if tt.parent != root {
+				flags := oteltrace.TraceFlags(0)
+				if tt.parent == sampledParent {
+					flags = oteltrace.FlagsSampled
+				}
+				ctx = oteltrace.ContextWithRemoteSpanContext(ctx, oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+					TraceID:    tt.traceID,
+					SpanID:     oteltrace.SpanID{9},
+					TraceFlags: flags,
+					Remote:     true,
+				}))
+			}
+			_, span := tp.Tracer("test").Start(ctx, "span")
+			span.End()
+			if got := span.SpanContext().IsSampled(); got != tt.want {
+				t.Errorf("표본 추출 = %v, want %v", got, tt.want)
+			}
+			if got := len(exp.GetSpans()) == 1; got != tt.want {
+				t.Errorf("내보냄 = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/status"
+)
+
+// 사용 중인 SDK 버전에는 자체 관측(self-observability) 메트릭이 없어서 같은 정보를
+// 익스포터와 프로세서를 감싸 직접 기록합니다. 이름은 OpenTelemetry의 SDK 메트릭
+// 시맨틱 규약 초안을 따릅니다. OTEL_SAMPLE_SDK_METRICS를 켰을 때만 값이 기록됩니다.
+var (
+	sdkLiveSpans    metric.Int64UpDownCounter
+	sdkExportedSpan metric.Int64Counter
+	sdkExportedLogs metric.Int64Counter
+)
+
+func init() {
+	var err error
+	sdkLiveSpans, err = meter.Int64UpDownCounter("otel.sdk.span.live",
+		metric.WithDescription("시작했지만 아직 끝나지 않은 기록 중인 스팬 수"),
+		metric.WithUnit("{span}"))
+	if err != nil {
+		panic(err)
+	}
+	sdkExportedSpan, err = meter.Int64Counter("otel.sdk.exporter.span.exported",
+		metric.WithDescription("익스포터로 넘긴 스팬 수. 실패한 배치는 error.type 속성이 붙습니다"),
+		metric.WithUnit("{span}"))
+	if err != nil {
+		panic(err)
+	}
+	sdkExportedLogs, err = meter.Int64Counter("otel.sdk.exporter.log.exported",
+		metric.WithDescription("익스포터로 넘긴 로그 레코드 수. 실패한 배치는 error.type 속성이 붙습니다"),
+		metric.WithUnit("{log_record}"))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// exportAttributes는 내보내기 결과를 속성으로 바꿉니다. 성공이면 속성이 없습니다.
+func exportAttributes(err error) metric.MeasurementOption {
+	if err == nil {
+		return metric.WithAttributes()
+	}
+	errType := status.Code(err).String()
+	if errors.Is(err, context.DeadlineExceeded) {
+		errType = "timeout"
+	}
+	return metric.WithAttributes(attribute.String("error.type", errType))
+}
+
+// liveSpanProcessor는 기록 중인 스팬 수를 셉니다. 값이 계속 늘면 끝나지 않는 스팬이 있다는 뜻입니다.
+type liveSpanProcessor struct{}
+
+func (liveSpanProcessor) OnStart(ctx context.Context, _ trace.ReadWriteSpan) {
+	sdkLiveSpans.Add(ctx, 1)
+}
+
+func (liveSpanProcessor) OnEnd(trace.ReadOnlySpan) {
+	sdkLiveSpans.Add(context.Background(), -1)
+}
+
+func (liveSpanProcessor) Shutdown(context.Context) error   { return nil }
+func (liveSpanProcessor) ForceFlush(context.Context) error { return nil }
+
+// countingSpanExporter는 내보낸 스팬 수를 결과별로 셉니다.
+// 배치 프로세서가 큐가 넘쳐 버린 스팬은 익스포터까지 오지 않으므로, 시작된 스팬 수와
+// 비교해 버려진 스팬을 가늠할 수 있습니다.
+type countingSpanExporter struct {
+	trace.SpanExporter
+}
+
+func (e countingSpanExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	sdkExportedSpan.Add(context.Background(), int64(len(spans)), exportAttributes(err))
+	return err
+}
+
+// countingLogExporter는 내보낸 로그 레코드 수를 결과별로 셉니다.
+type countingLogExporter struct {
+	log.Exporter
+}
+
+func (e countingLogExporter) Export(ctx context.Context, records []log.Record) error {
+	err := e.Exporter.Export(ctx, records)
+	sdkExportedLogs.Add(context.Background(), int64(len(records)), exportAttributes(err))
+	return err
+}
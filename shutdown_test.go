@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startTestServer는 임의 포트에서 h를 제공하는 서버를 시작하고, Serve가 끝나면 닫히는 채널을 반환합니다.
+func startTestServer(t *testing.T, h http.Handler) (*http.Server, string, <-chan error) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &http.Server{Handler: h}
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ln) }()
+	return s, "http://" + ln.Addr().String(), done
+}
+
+func TestListenAndShutdownConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		args        []string
+		wantAddr    string
+		wantTimeout time.Duration
+		wantErr     string
+	}{
+		{name: "default", wantAddr: ":8080", wantTimeout: 10 * time.Second},
+		{
+			name:        "env",
+			env:         map[string]string{"OTEL_SAMPLE_LISTEN_ADDR": "127.0.0.1:9000", "OTEL_SAMPLE_SHUTDOWN_TIMEOUT": "3s"},
+			wantAddr:    "127.0.0.1:9000",
+			wantTimeout: 3 * time.Second,
+		},
+		{
+			name:        "flags override env",
+			env:         map[string]string{"OTEL_SAMPLE_LISTEN_ADDR": "127.0.0.1:9000"},
+			args:        []string{"-addr", ":0", "-shutdown-timeout", "0"},
+			wantAddr:    ":0",
+			wantTimeout: 0,
+		},
+		{name: "negative timeout", args: []string{"-shutdown-timeout", "-1s"}, wantErr: "-shutdown-timeout: 음수일 수 없습니다"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := loadConfig(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("에러 = %v, want %q 포함", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadConfig: %v", err)
+			}
+			if cfg.ListenAddr != tt.wantAddr || cfg.ShutdownTimeout != tt.wantTimeout {
+				t.Errorf("주소, 제한 시간 = %q, %v, want %q, %v", cfg.ListenAddr, cfg.ShutdownTimeout, tt.wantAddr, tt.wantTimeout)
+			}
+		})
+	}
+}
+
+func TestShutdownServers(t *testing.T) {
+	tests := []struct {
+		name       string
+		slowApp    bool
+		wantForced bool
+	}{
+		{"idle", false, false},
+		{"in-flight request past the deadline", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			defer close(release)
+			started := make(chan struct{})
+			app, appURL, appDone := startTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				<-release
+			}))
+			metrics, _, metricsDone := startTestServer(t, http.NotFoundHandler())
+
+			if tt.slowApp {
+				go http.Get(appURL)
+				<-started
+			}
+
+			err := shutdownServers([]*http.Server{app, metrics}, []time.Duration{50 * time.Millisecond, time.Second})
+			if got := errors.Is(err, errForcedShutdown); got != tt.wantForced {
+				t.Errorf("shutdownServers() = %v, 강제 종료 %v, want %v", err, got, tt.wantForced)
+			}
+			// 한쪽이 강제로 닫혀도 다른 서버까지 모두 멈춰야 합니다.
+			for name, done := range map[string]<-chan error{"app": appDone, "metrics": metricsDone} {
+				select {
+				case err := <-done:
+					if !errors.Is(err, http.ErrServerClosed) {
+						t.Errorf("%s Serve() = %v, want ErrServerClosed", name, err)
+					}
+				case <-time.After(time.Second):
+					t.Errorf("%s 서버가 멈추지 않았습니다", name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/log/global"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// flusher는 ForceFlush를 지원하는 SDK 제공자입니다.
+type flusher interface {
+	ForceFlush(context.Context) error
+}
+
+// shutdownTrace는 정상 종료 과정을 "shutdown" 스팬으로 남깁니다.
+// 단계(서버 드레인, 텔레메트리 플러시)마다 자식 스팬을 만들고, 종료를 일으킨 원인과
+// 결과를 루트 스팬에 기록합니다. nil이면 아무것도 하지 않으므로 꺼져 있을 때도
+// 호출하는 쪽에서 확인할 필요가 없습니다.
+//
+// 이 스팬은 추적 제공자가 종료되기 전에 끝나고 동기적으로 플러시되어야 합니다.
+// 제공자의 Shutdown 자체는 스팬이 내보내진 뒤에 일어나므로 스팬에 담을 수 없어
+// 대신 표준 로그에 걸린 시간을 남깁니다(run 참고).
+type shutdownTrace struct {
+	ctx  context.Context
+	span oteltrace.Span
+}
+
+// startShutdownTrace는 enabled일 때 trigger(예: "signal:interrupt")를 원인으로 기록하는 스팬을 시작합니다.
+func startShutdownTrace(enabled bool, trigger string) *shutdownTrace {
+	if !enabled {
+		return nil
+	}
+	// 요청 컨텍스트는 이미 취소되었으므로 새 루트 스팬으로 시작합니다.
+	ctx, span := tracer.Start(context.Background(), "shutdown",
+		oteltrace.WithNewRoot(),
+		oteltrace.WithAttributes(attribute.String("shutdown.trigger", trigger)))
+	return &shutdownTrace{ctx: ctx, span: span}
+}
+
+// phase는 fn을 name 자식 스팬 안에서 실행합니다.
+func (t *shutdownTrace) phase(name string, fn func() error) error {
+	if t == nil {
+		return fn()
+	}
+	_, span := tracer.Start(t.ctx, "shutdown."+name)
+	err := fn()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+	return err
+}
+
+// end는 결과를 기록해 스팬을 끝내고, 추적 제공자를 플러시해 스팬을 바로 내보냅니다.
+func (t *shutdownTrace) end(err error) {
+	if t == nil {
+		return
+	}
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+		t.span.RecordError(err)
+		t.span.SetStatus(codes.Error, err.Error())
+	}
+	t.span.SetAttributes(attribute.String("shutdown.outcome", outcome))
+	t.span.End()
+	if tp, ok := otel.GetTracerProvider().(flusher); ok {
+		if err := tp.ForceFlush(context.Background()); err != nil {
+			log.Printf("종료 스팬 플러시 실패: %v", err)
+		}
+	}
+}
+
+// flushTelemetry는 제공자를 종료하기 전에 쌓인 스팬과 로그를 내보냅니다.
+// 종료 스팬이 켜져 있을 때 플러시 단계에 걸린 시간을 따로 재기 위해 씁니다.
+func flushTelemetry(ctx context.Context) error {
+	var err error
+	if tp, ok := otel.GetTracerProvider().(flusher); ok {
+		err = errors.Join(err, tp.ForceFlush(ctx))
+	}
+	if lp, ok := global.GetLoggerProvider().(flusher); ok {
+		err = errors.Join(err, lp.ForceFlush(ctx))
+	}
+	return err
+}
+
+type shutdownStartKey struct{}
+
+// withShutdownStart는 정상 종료를 시작한 시각을 ctx에 담아 제공자 종료 함수에 넘깁니다.
+func withShutdownStart(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, shutdownStartKey{}, start)
+}
+
+// logShutdownDuration은 ctx에 담긴 시작 시각부터 지금까지, 즉 드레인, 플러시, 추적과 측정
+// 제공자 종료에 걸린 시간을 shutdown.duration(초) 로그로 남깁니다. 측정 제공자는 이미
+// 종료되어 메트릭으로는 기록할 수 없으므로, 가장 나중에 종료되는 로거 제공자로 보냅니다.
+// 로거 제공자의 Shutdown보다 먼저 등록되어야 이 로그가 내보내집니다(setupOTelSDK 참고).
+// 시작 시각이 없으면(설정 실패 등으로 정상 종료가 아닌 경우) 아무것도 하지 않습니다.
+func logShutdownDuration(ctx context.Context) error {
+	start, ok := ctx.Value(shutdownStartKey{}).(time.Time)
+	if !ok {
+		return nil
+	}
+	d := time.Since(start)
+	log.Printf("정상 종료에 %v 걸렸습니다", d)
+	logger.LogAttrs(ctx, slog.LevelInfo, "정상 종료 완료", slog.Float64("shutdown.duration", d.Seconds()))
+	return nil
+}
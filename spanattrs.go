@@ -0,0 +1,137 @@
+package main
+
+import (
+	"slices"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// attributeDropProcessor는 끝난 스팬에서 지정한 속성 키를 지운 뒤 다음 SpanProcessor로 넘깁니다.
+// 요청 ID처럼 계측 라이브러리가 붙이는 고유 값이 스팬 크기와 백엔드 비용을 키울 때 씁니다.
+//
+// ReadOnlySpan은 바꿀 수 없으므로 속성만 바꾼 래퍼를 넘깁니다. 이 프로세서를 거치지
+// 않는 다른 프로세서(예: SIGQUIT 덤프)는 원래 속성을 그대로 봅니다.
+type attributeDropProcessor struct {
+	trace.SpanProcessor
+	keys map[attribute.Key]struct{}
+}
+
+// newAttributeDropProcessor는 keys에 해당하는 속성을 지우도록 next를 감쌉니다.
+// keys가 비어 있으면 next를 그대로 반환합니다.
+func newAttributeDropProcessor(next trace.SpanProcessor, keys []string) trace.SpanProcessor {
+	if len(keys) == 0 {
+		return next
+	}
+	set := make(map[attribute.Key]struct{}, len(keys))
+	for _, k := range keys {
+		set[attribute.Key(k)] = struct{}{}
+	}
+	return &attributeDropProcessor{SpanProcessor: next, keys: set}
+}
+
+func (p *attributeDropProcessor) OnEnd(s trace.ReadOnlySpan) {
+	attrs := s.Attributes()
+	kept := make([]attribute.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		if _, drop := p.keys[kv.Key]; !drop {
+			kept = append(kept, kv)
+		}
+	}
+	if len(kept) == len(attrs) {
+		p.SpanProcessor.OnEnd(s)
+		return
+	}
+	p.SpanProcessor.OnEnd(attributeOverrideSpan{
+		ReadOnlySpan: s,
+		attrs:        kept,
+		dropped:      s.DroppedAttributes() + len(attrs) - len(kept),
+	})
+}
+
+// attributeOverrideSpan은 속성만 바꿔 보여 주는 ReadOnlySpan입니다.
+// 지운 속성은 버려진 속성 수에 더해 백엔드에서도 잘렸다는 것을 알 수 있게 합니다.
+type attributeOverrideSpan struct {
+	trace.ReadOnlySpan
+	attrs   []attribute.KeyValue
+	dropped int
+}
+
+func (s attributeOverrideSpan) Attributes() []attribute.KeyValue { return s.attrs }
+func (s attributeOverrideSpan) DroppedAttributes() int           { return s.dropped }
+
+// truncationSuffix는 잘린 문자열 값 끝에 붙는 표시입니다.
+const truncationSuffix = "…"
+
+// attributeTruncateProcessor는 끝난 스팬의 문자열(과 문자열 배열) 속성 값이 maxLen 글자를
+// 넘으면 잘라 끝에 "…"을 붙인 뒤 다음 SpanProcessor로 넘깁니다. 하나라도 잘렸으면
+// app.attributes.truncated=true를 더합니다. 긴 플레이어 이름이나 URL이 백엔드의 값 길이
+// 제한에 걸리지 않게 합니다. 길이는 바이트가 아닌 글자(rune) 수이며 "…"도 한 글자로 셉니다.
+type attributeTruncateProcessor struct {
+	trace.SpanProcessor
+	maxLen int
+}
+
+// newAttributeTruncateProcessor는 maxLen 글자로 속성 값을 자르도록 next를 감쌉니다.
+// maxLen이 0이면 next를 그대로 반환합니다.
+func newAttributeTruncateProcessor(next trace.SpanProcessor, maxLen int) trace.SpanProcessor {
+	if maxLen <= 0 {
+		return next
+	}
+	return &attributeTruncateProcessor{SpanProcessor: next, maxLen: maxLen}
+}
+
+func (p *attributeTruncateProcessor) OnEnd(s trace.ReadOnlySpan) {
+	attrs := s.Attributes()
+	var out []attribute.KeyValue
+	for i, kv := range attrs {
+		v, ok := p.truncate(kv.Value)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = slices.Clone(attrs)
+		}
+		out[i] = attribute.KeyValue{Key: kv.Key, Value: v}
+	}
+	if out == nil {
+		p.SpanProcessor.OnEnd(s)
+		return
+	}
+	p.SpanProcessor.OnEnd(attributeOverrideSpan{
+		ReadOnlySpan: s,
+		attrs:        append(out, attribute.Bool("app.attributes.truncated", true)),
+		dropped:      s.DroppedAttributes(),
+	})
+}
+
+// truncate는 v가 너무 길면 자른 값과 true를 반환합니다.
+func (p *attributeTruncateProcessor) truncate(v attribute.Value) (attribute.Value, bool) {
+	switch v.Type() {
+	case attribute.STRING:
+		if s, ok := truncateString(v.AsString(), p.maxLen); ok {
+			return attribute.StringValue(s), true
+		}
+	case attribute.STRINGSLICE:
+		ss := v.AsStringSlice()
+		changed := false
+		for i, s := range ss {
+			if t, ok := truncateString(s, p.maxLen); ok {
+				ss[i], changed = t, true
+			}
+		}
+		if changed {
+			return attribute.StringSliceValue(ss), true
+		}
+	}
+	return v, false
+}
+
+func truncateString(s string, maxLen int) (string, bool) {
+	if utf8.RuneCountInString(s) <= maxLen {
+		return s, false
+	}
+	runes := []rune(s)
+	return string(runes[:maxLen-1]) + truncationSuffix, true
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestAttributeDropProcessor(t *testing.T) {
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.id", "abc"),
+		attribute.String("user_agent.original", "curl"),
+		attribute.String("player", "alice"),
+	}
+	tests := []struct {
+		name        string
+		keys        []string
+		wantKept    []attribute.Key
+		wantDropped int
+	}{
+		{"none", nil, []attribute.Key{"http.request.id", "user_agent.original", "player"}, 0},
+		{"one", []string{"http.request.id"}, []attribute.Key{"user_agent.original", "player"}, 1},
+		{"several", []string{"http.request.id", "user_agent.original", "missing"}, []attribute.Key{"player"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, exp := newRecordingTracerProvider(t, func(next sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+				return newAttributeDropProcessor(next, tt.keys)
+			})
+			_, span := tp.Tracer("test").Start(context.Background(), "span", trace.WithAttributes(attrs...))
+			span.End()
+
+			spans := exp.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("스팬 %d개, want 1", len(spans))
+			}
+			var got []attribute.Key
+			for _, kv := range spans[0].Attributes {
+				got = append(got, kv.Key)
+			}
+			if !slices.Equal(got, tt.wantKept) {
+				t.Errorf("남은 속성 = %v, want %v", got, tt.wantKept)
+			}
+			if spans[0].DroppedAttributes != tt.wantDropped {
+				t.Errorf("DroppedAttributes = %d, want %d", spans[0].DroppedAttributes, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestAttributeTruncateProcessor(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxLen        int
+		attr          attribute.KeyValue
+		want          attribute.Value
+		wantTruncated bool
+	}{
+		{"short", 5, attribute.String("player", "alice"), attribute.StringValue("alice"), false},
+		{"over length", 5, attribute.String("player", "alexander"), attribute.StringValue("alex…"), true},
+		// 길이는 바이트가 아니라 글자 수로 셉니다.
+		{"multibyte", 3, attribute.String("player", "주사위놀이"), attribute.StringValue("주사…"), true},
+		{"string slice", 4, attribute.StringSlice("tags", []string{"ok", "toolong"}), attribute.StringSliceValue([]string{"ok", "too…"}), true},
+		{"non-string", 1, attribute.Int("roll.value", 123456), attribute.IntValue(123456), false},
+		{"disabled", 0, attribute.String("player", "alexander"), attribute.StringValue("alexander"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, exp := newRecordingTracerProvider(t, func(next sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+				return newAttributeTruncateProcessor(next, tt.maxLen)
+			})
+			_, span := tp.Tracer("test").Start(context.Background(), "span", trace.WithAttributes(tt.attr))
+			span.End()
+
+			s := exp.GetSpans()[0]
+			assertSpanAttr(t, s, tt.attr.Key, tt.want)
+			_, truncated := spanAttr(s, "app.attributes.truncated")
+			if truncated != tt.wantTruncated {
+				t.Errorf("app.attributes.truncated 있음 = %v, want %v", truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestAttributeTruncateDefault(t *testing.T) {
+	// 기본 상한은 넉넉해서 보통 값은 건드리지 않고, 아주 긴 값만 자릅니다.
+	cfg := defaultConfig()
+	tp, exp := newRecordingTracerProvider(t, func(next sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+		return newAttributeTruncateProcessor(next, cfg.AttributeValueMaxLength)
+	})
+	long := strings.Repeat("x", cfg.AttributeValueMaxLength+100)
+	_, span := tp.Tracer("test").Start(context.Background(), "span", trace.WithAttributes(
+		attribute.String("url.full", "http://localhost:8080/rolldice/alice"),
+		attribute.String("player", long)))
+	span.End()
+
+	s := exp.GetSpans()[0]
+	assertSpanAttr(t, s, "url.full", attribute.StringValue("http://localhost:8080/rolldice/alice"))
+	v, _ := spanAttr(s, "player")
+	if n := utf8.RuneCountInString(v.AsString()); n != cfg.AttributeValueMaxLength || !strings.HasSuffix(v.AsString(), truncationSuffix) {
+		t.Errorf("잘린 player 길이 = %d, want %d와 %q로 끝남", n, cfg.AttributeValueMaxLength, truncationSuffix)
+	}
+}
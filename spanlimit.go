@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanBudget은 한 요청이 만들 수 있는 자식 스팬 수를 셉니다.
+// 이 프로세스가 볼 수 있는 것은 자신이 처리하는 요청뿐이므로, 상한은 트레이스
+// 전체가 아니라 트레이스 중 이 서비스가 만드는 부분에 적용됩니다.
+type spanBudget struct {
+	limit     int64
+	started   atomic.Int64
+	truncated atomic.Int64
+}
+
+type spanBudgetKey struct{}
+
+// startSpan은 tracer.Start와 같지만 요청의 스팬 예산을 다 쓰면 스팬을 만들지 않습니다.
+// 이때는 기록하지 않는 스팬과 원래 ctx를 반환하므로, 그 아래에서 만드는 스팬은
+// 가장 가까운 기록된 스팬에 붙고 호출하는 쪽의 span.End()도 그대로 쓸 수 있습니다.
+func startSpan(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if b, ok := ctx.Value(spanBudgetKey{}).(*spanBudget); ok && b.started.Add(1) > b.limit {
+		b.truncated.Add(1)
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+	return tracer.Start(ctx, spanName, opts...)
+}
+
+// limitSpans는 요청마다 최대 limit개의 자식 스팬만 만들도록 예산을 컨텍스트에 담습니다.
+// 잘린 스팬이 있으면 서버 스팬에 truncated_spans 속성으로 그 수를 남깁니다.
+// 서버 스팬이 필요하므로 HTTP 계측 안쪽에 둬야 합니다.
+func limitSpans(limit int, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b := &spanBudget{limit: int64(limit)}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), spanBudgetKey{}, b)))
+		if n := b.truncated.Load(); n > 0 {
+			trace.SpanFromContext(r.Context()).SetAttributes(attribute.Int64("truncated_spans", n))
+		}
+	})
+}
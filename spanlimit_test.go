@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestLimitSpans(t *testing.T) {
+	// 벤치 경로는 요청마다 "bench work"와 "roll" 두 자식 스팬을 만듭니다.
+	tests := []struct {
+		limit         int
+		wantChildren  []string
+		wantTruncated int64
+	}{
+		{0, []string{"bench work", "roll"}, 0},
+		{1, []string{"bench work"}, 1},
+		{2, []string{"bench work", "roll"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.limit), func(t *testing.T) {
+			resetTelemetry(t)
+			cfg := defaultConfig()
+			cfg.Bench = true
+			cfg.MaxSpansPerTrace = tt.limit
+			if w := serve(newTestHandler(cfg), httptest.NewRequest(http.MethodGet, "/rolldice/bench", nil)); w.Code != http.StatusOK {
+				t.Fatalf("상태 = %d, want 200", w.Code)
+			}
+
+			// 기록 쿼리처럼 계측 라이브러리가 만드는 스팬은 예산에 들지 않으므로 이 서비스의 스팬만 봅니다.
+			var children []string
+			for _, s := range endedSpans() {
+				if s.Parent.IsValid() && s.InstrumentationScope.Name == name {
+					children = append(children, s.Name)
+				}
+			}
+			if !slices.Equal(children, tt.wantChildren) {
+				t.Errorf("자식 스팬 = %q, want %q", children, tt.wantChildren)
+			}
+			server := findSpan(t, "GET /rolldice/bench")
+			if tt.wantTruncated == 0 {
+				if v, ok := spanAttr(server, "truncated_spans"); ok {
+					t.Errorf("truncated_spans = %s, want 없음", v.Emit())
+				}
+				return
+			}
+			assertSpanAttr(t, server, "truncated_spans", attribute.Int64Value(tt.wantTruncated))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// nameSpanByRoute는 서버 스팬 이름을 "{메서드} {경로 패턴}"으로 바꿉니다.
+//
+// otelhttp.NewHandler(mux, "/")는 라우팅 전에 스팬을 시작하므로 스팬 이름은 항상
+// 연산 이름 "/"가 되고, WithRouteTag는 http.route 속성만 채웁니다. 그래서 이름만으로는
+// 어떤 경로였는지 알 수 없습니다. 라우팅이 끝난 핸들러에서 이름을 바꿔 시맨틱 규약의
+// "{method} {http.route}" 형식을 따르게 합니다. 경로를 모르는 요청(not_found)은
+// 카디널리티가 커지지 않도록 메서드만 씁니다.
+func nameSpanByRoute(pattern string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trace.SpanFromContext(r.Context()).SetName(routeSpanName(r.Method, pattern))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// routeSpanName은 메서드와 경로 패턴으로 스팬 이름을 만듭니다.
+// "POST /admin/loglevel"처럼 패턴에 이미 메서드가 있으면 그대로 씁니다.
+func routeSpanName(method, pattern string) string {
+	switch {
+	case pattern == "":
+		return method
+	case strings.Contains(pattern, " "):
+		return pattern
+	default:
+		return method + " " + pattern
+	}
+}
+
+// tagRoutePattern은 서버 스팬에 ServeMux에 등록한 패턴을 그대로 server.route.pattern
+// 속성으로 남깁니다. "POST /admin/loglevel"처럼 메서드가 붙은 패턴도 손대지 않으므로,
+// 요청이 어느 등록 항목에 일치했는지 라우팅 문제를 디버깅할 때 볼 수 있습니다.
+// r.Pattern은 Go 1.23부터 있으므로 등록할 때 알고 있는 패턴을 넘겨받습니다.
+func tagRoutePattern(pattern string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("server.route.pattern", pattern))
+		next.ServeHTTP(w, r)
+	})
+}
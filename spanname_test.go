@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// serverSpanName은 마지막으로 끝난 루트 스팬의 이름을 반환합니다.
+func serverSpanName(t *testing.T) string {
+	t.Helper()
+	spans := endedSpans()
+	for i := len(spans) - 1; i >= 0; i-- {
+		if !spans[i].Parent.IsValid() {
+			return spans[i].Name
+		}
+	}
+	t.Fatal("서버 스팬이 없습니다")
+	return ""
+}
+
+func TestServerSpanName(t *testing.T) {
+	tests := []struct {
+		mode   string
+		method string
+		path   string
+		want   string
+	}{
+		{"route", http.MethodGet, "/rolldice/", "GET /rolldice/"},
+		{"route", http.MethodGet, "/rolldice/alice", "GET /rolldice/{player}"},
+		{"route", http.MethodGet, "/admin/loglevel", "GET /admin/loglevel"},
+		// 경로를 모르는 요청은 카디널리티가 커지지 않도록 메서드만 씁니다.
+		{"route", http.MethodGet, "/no/such/path", "GET"},
+		{"route", http.MethodDelete, "/rolldice/alice", "DELETE /rolldice/{player}"},
+		{"operation", http.MethodGet, "/rolldice/alice", "/"},
+		{"operation", http.MethodGet, "/no/such/path", "/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode+" "+tt.method+" "+tt.path, func(t *testing.T) {
+			resetTelemetry(t)
+			cfg := defaultConfig()
+			cfg.ServerSpanName = tt.mode
+			cfg.Admin = true
+			serve(newTestHandler(cfg), httptest.NewRequest(tt.method, tt.path, nil))
+			if got := serverSpanName(t); got != tt.want {
+				t.Errorf("서버 스팬 이름 = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerSpanNameConfig(t *testing.T) {
+	t.Setenv("OTEL_SAMPLE_SERVER_SPAN_NAME", "path")
+	_, err := loadConfig(nil)
+	if err == nil || !strings.Contains(err.Error(), `OTEL_SAMPLE_SERVER_SPAN_NAME: 알 수 없는 방식 "path"`) {
+		t.Errorf("에러 = %v", err)
+	}
+}
+
+func TestRoutePatternAttribute(t *testing.T) {
+	tests := []struct {
+		enabled bool
+		method  string
+		path    string
+		want    string
+	}{
+		{true, http.MethodGet, "/rolldice/", "/rolldice/"},
+		{true, http.MethodGet, "/rolldice/alice", "/rolldice/{player}"},
+		// 메서드가 붙은 패턴은 메서드까지 그대로 남습니다.
+		{true, http.MethodPost, "/admin/loglevel?level=info", "POST /admin/loglevel"},
+		{false, http.MethodGet, "/rolldice/alice", ""},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %s/enabled=%v", tt.method, tt.path, tt.enabled), func(t *testing.T) {
+			resetTelemetry(t)
+			cfg := defaultConfig()
+			cfg.RoutePatternAttribute = tt.enabled
+			cfg.Admin = true
+			serve(newTestHandler(cfg), httptest.NewRequest(tt.method, tt.path, nil))
+
+			spans := endedSpans()
+			server := spans[len(spans)-1]
+			if server.Parent.IsValid() {
+				t.Fatalf("마지막 스팬 %q가 서버 스팬이 아닙니다", server.Name)
+			}
+			if tt.want == "" {
+				if v, ok := spanAttr(server, "server.route.pattern"); ok {
+					t.Errorf("꺼져 있는데 server.route.pattern = %q", v.AsString())
+				}
+				return
+			}
+			assertSpanAttr(t, server, "server.route.pattern", attribute.StringValue(tt.want))
+		})
+	}
+}
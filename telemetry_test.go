@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log/global"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"go-opentelemetry-sample/testutil"
+)
+
+// testTelemetry는 모든 테스트가 함께 쓰는 메모리 제공자입니다. 스팬 익스포터와 메트릭 리더는
+// testutil이 만들고, 검사 함수도 testutil의 것을 이 패키지의 전역에 맞춰 감쌉니다.
+//
+// 패키지의 tracer, meter, logger와 init에서 만든 계측기는 전역 제공자가 처음 설정될 때
+// 한 번만 연결되므로(setGlobalMeterProvider 참고), 테스트마다 제공자를 새로 만들지 않고
+// TestMain에서 한 번 설정한 뒤 테스트마다 resetTelemetry로 기록을 비웁니다.
+// 메트릭은 누적 값이므로 전후 값의 차이로 확인합니다.
+var testTelemetry struct {
+	*testutil.Telemetry
+	meterProvider *sdkmetric.MeterProvider
+	logs          *logRecorder
+}
+
+func TestMain(m *testing.M) {
+	testTelemetry.Telemetry = testutil.New()
+	otel.SetTracerProvider(testTelemetry.TracerProvider())
+
+	// /metrics 테스트도 같은 값을 보도록 실제와 같이 Prometheus 리더를 함께 붙입니다.
+	mp, err := newMeterProvider(defaultConfig(), nil, resource.Empty(), testTelemetry.MeterProviderOption())
+	if err != nil {
+		panic(err)
+	}
+	testTelemetry.meterProvider = mp
+	setGlobalMeterProvider(mp)
+
+	testTelemetry.logs = &logRecorder{}
+	global.SetLoggerProvider(sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewSimpleProcessor(testTelemetry.logs))))
+	otel.SetTextMapPropagator(newPropagator(defaultConfig()))
+
+	os.Exit(m.Run())
+}
+
+// resetTelemetry는 지금까지 기록된 스팬과 로그를 비웁니다.
+func resetTelemetry(t *testing.T) {
+	t.Helper()
+	testTelemetry.Reset()
+	testTelemetry.logs.reset()
+}
+
+// testDiceInstruments는 테스트가 함께 쓰는 주사위 계측기입니다.
+// 같은 이름의 계측기를 여러 번 만들지 않도록 한 번만 만듭니다.
+var testDiceInstruments = sync.OnceValue(func() *diceInstruments {
+	inst, err := newDiceInstruments(meter)
+	if err != nil {
+		panic(err)
+	}
+	return inst
+})
+
+// testRollStore는 테스트가 함께 쓰는 메모리 SQLite 던지기 기록 저장소입니다.
+// DB 통계 콜백이 저장소마다 전역 측정 제공자에 쌓이지 않도록 한 번만 엽니다.
+var testRollStore = sync.OnceValue(func() *rollStore {
+	store, err := openRollStore(context.Background(), ":memory:")
+	if err != nil {
+		panic(err)
+	}
+	return store
+})
+
+// newTestHandler는 cfg로 앱 핸들러를 만듭니다. 준비 상태는 켜 두고 testRollStore에 기록합니다.
+func newTestHandler(cfg config) http.Handler {
+	ready := &readiness{}
+	ready.set(true)
+	return newHTTPHandler(cfg, ready, testDiceInstruments(), testRollStore())
+}
+
+// serve는 h로 요청 하나를 처리한 응답을 반환합니다.
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+// newRecordingTracerProvider는 전역과 별개로, wrap이 감싼 프로세서를 거쳐 메모리에
+// 내보내는 제공자를 만듭니다. 스팬 프로세서와 샘플러를 따로 시험할 때 씁니다.
+func newRecordingTracerProvider(t *testing.T, wrap func(sdktrace.SpanProcessor) sdktrace.SpanProcessor, opts ...sdktrace.TracerProviderOption) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	t.Helper()
+	exp := tracetest.NewInMemoryExporter()
+	var sp sdktrace.SpanProcessor = sdktrace.NewSimpleSpanProcessor(exp)
+	if wrap != nil {
+		sp = wrap(sp)
+	}
+	tp := sdktrace.NewTracerProvider(append([]sdktrace.TracerProviderOption{sdktrace.WithSpanProcessor(sp)}, opts...)...)
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+	return tp, exp
+}
+
+// endedSpans는 지금까지 끝난 스팬을 반환합니다.
+func endedSpans() tracetest.SpanStubs {
+	return testTelemetry.Spans.GetSpans()
+}
+
+// findSpan은 이름이 spanName인 마지막 스팬을 찾고, 없으면 테스트를 멈춥니다.
+func findSpan(t *testing.T, spanName string) tracetest.SpanStub {
+	t.Helper()
+	return testutil.AssertSpanWithName(t, testTelemetry.Spans, spanName)
+}
+
+// spanAttr은 스팬 속성 key의 값을 반환합니다.
+func spanAttr(s tracetest.SpanStub, key attribute.Key) (attribute.Value, bool) {
+	return testutil.SpanAttribute(s, key)
+}
+
+// assertSpanAttr은 스팬 속성 key가 want인지 확인합니다.
+func assertSpanAttr(t *testing.T, s tracetest.SpanStub, key attribute.Key, want attribute.Value) {
+	t.Helper()
+	testutil.AssertSpanAttribute(t, s, key, want)
+}
+
+// collectMetrics는 수동 리더로 지금까지의 누적 메트릭을 수집합니다.
+func collectMetrics(t *testing.T) metricdata.ResourceMetrics {
+	t.Helper()
+	return testutil.Collect(t, testTelemetry.Metrics)
+}
+
+// findMetric은 이름이 metricName인 메트릭을 찾습니다.
+func findMetric(rm metricdata.ResourceMetrics, metricName string) (metricdata.Metrics, bool) {
+	return testutil.FindMetric(rm, metricName)
+}
+
+// counterValue는 정수 합계 메트릭 metricName 중 attrs를 모두 가진 데이터 포인트의 합을 반환합니다.
+// 메트릭이 아직 없으면 0입니다.
+func counterValue(t *testing.T, metricName string, attrs ...attribute.KeyValue) int64 {
+	t.Helper()
+	return testutil.CounterValue(t, testTelemetry.Metrics, metricName, attrs...)
+}
+
+// counterAttributes는 정수 합계 메트릭 metricName 중 attrs를 모두 가진 데이터 포인트의 속성 집합을 반환합니다.
+func counterAttributes(t *testing.T, metricName string, attrs ...attribute.KeyValue) []attribute.Set {
+	t.Helper()
+	return testutil.CounterAttributes(t, testTelemetry.Metrics, metricName, attrs...)
+}
+
+// histogramCount는 히스토그램 metricName 중 attrs를 모두 가진 데이터 포인트의 기록 수를 반환합니다.
+func histogramCount(t *testing.T, metricName string, attrs ...attribute.KeyValue) uint64 {
+	t.Helper()
+	return testutil.HistogramCount(t, testTelemetry.Metrics, metricName, attrs...)
+}
+
+// logRecorder는 내보낸 로그 레코드를 메모리에 모으는 익스포터입니다.
+type logRecorder struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (r *logRecorder) Export(_ context.Context, records []sdklog.Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rec := range records {
+		r.records = append(r.records, rec.Clone())
+	}
+	return nil
+}
+
+func (r *logRecorder) Shutdown(context.Context) error   { return nil }
+func (r *logRecorder) ForceFlush(context.Context) error { return nil }
+
+func (r *logRecorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = nil
+}
+
+// get은 지금까지 모은 레코드의 복사본을 반환합니다.
+func (r *logRecorder) get() []sdklog.Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]sdklog.Record(nil), r.records...)
+}
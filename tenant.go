@@ -0,0 +1,74 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// 리소스는 프로세스 전체에 하나뿐이라 요청마다 바꿀 수 없습니다. 여러 테넌트가 한
+// 프로세스를 나눠 쓰면 테넌트별 service.namespace 같은 값은 리소스 대신 스팬 속성으로
+// 남깁니다. 백엔드에서 리소스 속성과 같은 키의 스팬 속성으로 걸러 격리합니다.
+
+type tenantKey struct{}
+
+// tenantHandler는 header 요청 헤더의 테넌트 ID를 요청 컨텍스트에 담습니다.
+// 서버 스팬에도 속성이 붙도록 HTTP 계측보다 바깥에 둬야 합니다.
+func tenantHandler(header string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tenant := r.Header.Get(header); tenant != "" {
+			r = r.WithContext(context.WithValue(r.Context(), tenantKey{}, tenant))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tenantProcessor는 스팬이 시작될 때 부모 컨텍스트의 테넌트에 해당하는 속성을 붙입니다.
+// 같은 요청의 서버 스팬과 자식 스팬이 모두 같은 속성을 갖습니다. 파일에 없는 테넌트는
+// 속성을 붙이지 않으므로, 클라이언트가 헤더로 임의의 값을 주입할 수 없습니다.
+type tenantProcessor struct {
+	attrs map[string][]attribute.KeyValue
+}
+
+// newTenantProcessor는 테넌트 ID마다 스팬 속성을 담은 JSON 파일을 읽습니다.
+// 예: {"acme": {"service.namespace": "acme"}, "globex": {"service.namespace": "globex"}}
+func newTenantProcessor(path string) (*tenantProcessor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tenants map[string]map[string]string
+	if err := json.Unmarshal(data, &tenants); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	p := &tenantProcessor{attrs: make(map[string][]attribute.KeyValue, len(tenants))}
+	for tenant, kv := range tenants {
+		attrs := make([]attribute.KeyValue, 0, len(kv))
+		for k, v := range kv {
+			attrs = append(attrs, attribute.String(k, v))
+		}
+		// 맵 순회 순서와 관계없이 속성 순서가 같게 합니다.
+		slices.SortFunc(attrs, func(a, b attribute.KeyValue) int { return cmp.Compare(a.Key, b.Key) })
+		p.attrs[tenant] = attrs
+	}
+	return p, nil
+}
+
+func (p *tenantProcessor) OnStart(parent context.Context, s trace.ReadWriteSpan) {
+	tenant, _ := parent.Value(tenantKey{}).(string)
+	if attrs, ok := p.attrs[tenant]; ok {
+		s.SetAttributes(attrs...)
+	}
+}
+
+func (p *tenantProcessor) OnEnd(trace.ReadOnlySpan)         {}
+func (p *tenantProcessor) Shutdown(context.Context) error   { return nil }
+func (p *tenantProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTenantProcessor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tenants.json")
+	if err := os.WriteFile(path, []byte(`{
+		"acme": {"service.namespace": "acme", "tenant.tier": "gold"},
+		"globex": {"service.namespace": "globex"}
+	}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tenants, err := newTenantProcessor(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp, exp := newRecordingTracerProvider(t, nil, sdktrace.WithSpanProcessor(tenants))
+	tr := tp.Tracer("test")
+	h := tenantHandler("X-Tenant", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, server := tr.Start(r.Context(), "server")
+		_, child := tr.Start(ctx, "child")
+		child.End()
+		server.End()
+	}))
+
+	tests := []struct {
+		tenant string
+		want   map[attribute.Key]string
+	}{
+		{"acme", map[attribute.Key]string{"service.namespace": "acme", "tenant.tier": "gold"}},
+		{"globex", map[attribute.Key]string{"service.namespace": "globex"}},
+		// 파일에 없는 테넌트와 헤더가 없는 요청에는 아무 속성도 붙지 않습니다.
+		{"initech", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run("tenant="+tt.tenant, func(t *testing.T) {
+			exp.Reset()
+			r := httptest.NewRequest(http.MethodGet, "/rolldice/", nil)
+			if tt.tenant != "" {
+				r.Header.Set("X-Tenant", tt.tenant)
+			}
+			serve(h, r)
+
+			spans := exp.GetSpans()
+			if len(spans) != 2 {
+				t.Fatalf("스팬 %d개, want 2", len(spans))
+			}
+			// 서버 스팬과 자식 스팬이 같은 테넌트 속성을 가져야 합니다.
+			for _, s := range spans {
+				for key, want := range tt.want {
+					assertSpanAttr(t, s, key, attribute.StringValue(want))
+				}
+				for _, key := range []attribute.Key{"service.namespace", "tenant.tier"} {
+					if _, ok := tt.want[key]; ok {
+						continue
+					}
+					if v, ok := spanAttr(s, key); ok {
+						t.Errorf("%s 스팬에 %s = %q가 붙었습니다", s.Name, key, v.AsString())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestTenantProcessorFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"acme": "not an object"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{"missing", filepath.Join(dir, "missing.json"), "no such file"},
+		{"bad shape", bad, bad + ": json:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTenantProcessor(tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("에러 = %v, want %q 포함", err, tt.wantErr)
+			}
+		})
+	}
+	// 시작할 때 파일을 읽지 못하면 제공자도 만들지 않습니다.
+	cfg := defaultConfig()
+	cfg.TenantAttributesFile = bad
+	if _, err := newTraceProvider(context.Background(), cfg, resource.Empty(), tracetest.NewInMemoryExporter()); err == nil {
+		t.Error("newTraceProvider가 에러 없이 끝났습니다")
+	}
+}
@@ -0,0 +1,194 @@
+// Package testutil은 OpenTelemetry 계측을 시험하는 테스트가 함께 쓰는 메모리 제공자와
+// 검사 함수를 제공합니다.
+//
+// 스팬은 tracetest.InMemoryExporter에, 메트릭은 필요할 때 수집하는 sdkmetric.ManualReader에
+// 모읍니다. 메트릭은 누적 값이므로 테스트는 동작 전후 값을 비교합니다.
+package testutil
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Telemetry는 테스트가 스팬과 메트릭을 확인할 메모리 익스포터와 수동 리더입니다.
+type Telemetry struct {
+	Spans   *tracetest.InMemoryExporter
+	Metrics *sdkmetric.ManualReader
+}
+
+// New는 비어 있는 Telemetry를 만듭니다.
+func New() *Telemetry {
+	return &Telemetry{
+		Spans:   tracetest.NewInMemoryExporter(),
+		Metrics: sdkmetric.NewManualReader(),
+	}
+}
+
+// TracerProvider는 끝난 스팬을 바로 Spans로 내보내는 추적 제공자를 만듭니다.
+func (tel *Telemetry) TracerProvider(opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(append([]sdktrace.TracerProviderOption{sdktrace.WithSyncer(tel.Spans)}, opts...)...)
+}
+
+// MeterProviderOption은 측정 제공자에 Metrics 리더를 붙이는 옵션입니다.
+func (tel *Telemetry) MeterProviderOption() sdkmetric.Option {
+	return sdkmetric.WithReader(tel.Metrics)
+}
+
+// Reset은 지금까지 모은 스팬을 비웁니다. 누적 메트릭은 비울 수 없습니다.
+func (tel *Telemetry) Reset() {
+	tel.Spans.Reset()
+}
+
+// AssertSpanWithName은 이름이 name인 마지막 스팬을 찾고, 없으면 끝난 스팬 이름을 보여 주고 테스트를 멈춥니다.
+func AssertSpanWithName(t testing.TB, exp *tracetest.InMemoryExporter, name string) tracetest.SpanStub {
+	t.Helper()
+	spans := exp.GetSpans()
+	for i := len(spans) - 1; i >= 0; i-- {
+		if spans[i].Name == name {
+			return spans[i]
+		}
+	}
+	names := make([]string, len(spans))
+	for i, s := range spans {
+		names[i] = s.Name
+	}
+	t.Fatalf("%q 스팬이 없습니다. 끝난 스팬: %q", name, names)
+	return tracetest.SpanStub{}
+}
+
+// SpanAttribute는 스팬 속성 key의 값을 반환합니다.
+func SpanAttribute(s tracetest.SpanStub, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range s.Attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+// AssertSpanAttribute는 스팬 속성 key가 want인지 확인합니다.
+func AssertSpanAttribute(t testing.TB, s tracetest.SpanStub, key attribute.Key, want attribute.Value) {
+	t.Helper()
+	got, ok := SpanAttribute(s, key)
+	if !ok {
+		t.Errorf("%s 스팬에 %s 속성이 없습니다", s.Name, key)
+		return
+	}
+	if got != want {
+		t.Errorf("%s 스팬의 %s = %s, want %s", s.Name, key, got.Emit(), want.Emit())
+	}
+}
+
+// Collect는 reader로 지금까지의 메트릭을 수집합니다.
+func Collect(t testing.TB, reader sdkmetric.Reader) metricdata.ResourceMetrics {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("메트릭 수집: %v", err)
+	}
+	return rm
+}
+
+// FindMetric은 이름이 name인 메트릭을 찾습니다.
+func FindMetric(rm metricdata.ResourceMetrics, name string) (metricdata.Metrics, bool) {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+	return metricdata.Metrics{}, false
+}
+
+// HasAttributes는 set이 attrs를 모두 담고 있는지 확인합니다.
+func HasAttributes(set attribute.Set, attrs []attribute.KeyValue) bool {
+	for _, kv := range attrs {
+		if v, ok := set.Value(kv.Key); !ok || v != kv.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// counterPoints는 정수 합계 메트릭 name 중 attrs를 모두 가진 데이터 포인트를 반환합니다.
+func counterPoints(t testing.TB, reader sdkmetric.Reader, name string, attrs []attribute.KeyValue) []metricdata.DataPoint[int64] {
+	t.Helper()
+	m, ok := FindMetric(Collect(t, reader), name)
+	if !ok {
+		return nil
+	}
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("%s는 정수 합계가 아닙니다: %T", name, m.Data)
+	}
+	var points []metricdata.DataPoint[int64]
+	for _, dp := range sum.DataPoints {
+		if HasAttributes(dp.Attributes, attrs) {
+			points = append(points, dp)
+		}
+	}
+	return points
+}
+
+// CounterValue는 정수 합계 메트릭 name 중 attrs를 모두 가진 데이터 포인트의 합을 반환합니다.
+// 메트릭이 아직 없으면 0입니다.
+func CounterValue(t testing.TB, reader sdkmetric.Reader, name string, attrs ...attribute.KeyValue) int64 {
+	t.Helper()
+	var total int64
+	for _, dp := range counterPoints(t, reader, name, attrs) {
+		total += dp.Value
+	}
+	return total
+}
+
+// AssertCounterValue는 정수 합계 메트릭 name 중 attrs를 모두 가진 데이터 포인트의 합이 want인지 확인합니다.
+func AssertCounterValue(t testing.TB, reader sdkmetric.Reader, name string, want int64, attrs ...attribute.KeyValue) {
+	t.Helper()
+	if got := CounterValue(t, reader, name, attrs...); got != want {
+		t.Errorf("%s%v = %d, want %d", name, attrs, got, want)
+	}
+}
+
+// CounterAttributes는 정수 합계 메트릭 name 중 attrs를 모두 가진 데이터 포인트의 속성 집합을 반환합니다.
+func CounterAttributes(t testing.TB, reader sdkmetric.Reader, name string, attrs ...attribute.KeyValue) []attribute.Set {
+	t.Helper()
+	var sets []attribute.Set
+	for _, dp := range counterPoints(t, reader, name, attrs) {
+		sets = append(sets, dp.Attributes)
+	}
+	return sets
+}
+
+// HistogramCount는 히스토그램 name 중 attrs를 모두 가진 데이터 포인트의 기록 수를 반환합니다.
+func HistogramCount(t testing.TB, reader sdkmetric.Reader, name string, attrs ...attribute.KeyValue) uint64 {
+	t.Helper()
+	m, ok := FindMetric(Collect(t, reader), name)
+	if !ok {
+		return 0
+	}
+	var total uint64
+	switch h := m.Data.(type) {
+	case metricdata.Histogram[float64]:
+		for _, dp := range h.DataPoints {
+			if HasAttributes(dp.Attributes, attrs) {
+				total += dp.Count
+			}
+		}
+	case metricdata.Histogram[int64]:
+		for _, dp := range h.DataPoints {
+			if HasAttributes(dp.Attributes, attrs) {
+				total += dp.Count
+			}
+		}
+	default:
+		t.Fatalf("%s는 히스토그램이 아닙니다: %T", name, m.Data)
+	}
+	return total
+}
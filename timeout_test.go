@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouteTimeoutConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeout  string
+		timeouts string
+		want     map[string]time.Duration
+		wantErr  string
+	}{
+		{
+			name:     "override",
+			timeout:  "2s",
+			timeouts: "/rolldice/bench=30s",
+			want:     map[string]time.Duration{"/rolldice/bench": 30 * time.Second, "/rolldice/": 2 * time.Second},
+		},
+		{
+			name: "unset",
+			want: map[string]time.Duration{"/rolldice/": 0},
+		},
+		{name: "negative default", timeout: "-1s", wantErr: "OTEL_SAMPLE_ROUTE_TIMEOUT: 음수일 수 없습니다"},
+		{name: "negative override", timeouts: "/rolldice/=-1s", wantErr: "OTEL_SAMPLE_ROUTE_TIMEOUTS: /rolldice/: 음수일 수 없습니다"},
+		{name: "bad duration", timeouts: "/rolldice/=soon", wantErr: "OTEL_SAMPLE_ROUTE_TIMEOUTS: /rolldice/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.timeout != "" {
+				t.Setenv("OTEL_SAMPLE_ROUTE_TIMEOUT", tt.timeout)
+			}
+			if tt.timeouts != "" {
+				t.Setenv("OTEL_SAMPLE_ROUTE_TIMEOUTS", tt.timeouts)
+			}
+			cfg, err := loadConfig(nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("에러 = %v, want %q 포함", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadConfig: %v", err)
+			}
+			for pattern, want := range tt.want {
+				if got := cfg.routeTimeout(pattern); got != want {
+					t.Errorf("routeTimeout(%q) = %v, want %v", pattern, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRouteTimeoutHandler(t *testing.T) {
+	// 상대 서비스가 응답하지 않으면 versus 경로만 제한 시간에 걸립니다.
+	release := make(chan struct{})
+	opponent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer opponent.Close()
+	defer close(release)
+
+	cfg := defaultConfig()
+	cfg.VersusURL = opponent.URL
+	cfg.RouteTimeouts = map[string]time.Duration{"/rolldice/versus/{player}": 50 * time.Millisecond}
+	h := newTestHandler(cfg)
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/rolldice/versus/alice", http.StatusServiceUnavailable},
+		{"/rolldice/alice", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := serve(h, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.want {
+				t.Errorf("상태 = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
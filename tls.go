@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var tlsHandshakeCnt metric.Int64Counter
+
+func init() {
+	var err error
+	tlsHandshakeCnt, err = meter.Int64Counter("tls.server.handshakes",
+		metric.WithDescription("결과와 협상된 프로토콜별 TLS 핸드셰이크 수"),
+		metric.WithUnit("{handshake}"))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// tlsHandshakeRecorder는 http.Server.ConnState에 연결해 TLS 핸드셰이크 결과를 기록합니다.
+// 핸드셰이크가 끝난 뒤의 상태 전이에서만 연결 상태를 읽으므로 핸드셰이크를 막지 않습니다.
+//
+// 핸드셰이크에 성공한 연결은 첫 요청(StateActive)에서, 요청 없이 닫힌 연결은
+// StateClosed에서 HandshakeComplete 여부로 성공/실패를 판정합니다.
+type tlsHandshakeRecorder struct {
+	recorded sync.Map // net.Conn -> struct{}
+}
+
+func (t *tlsHandshakeRecorder) connState(c net.Conn, state http.ConnState) {
+	tc, ok := c.(*tls.Conn)
+	if !ok {
+		return
+	}
+	switch state {
+	case http.StateActive:
+		if _, loaded := t.recorded.LoadOrStore(c, struct{}{}); !loaded {
+			t.record(tc.ConnectionState())
+		}
+	case http.StateClosed, http.StateHijacked:
+		if _, loaded := t.recorded.LoadAndDelete(c); !loaded {
+			t.record(tc.ConnectionState())
+		}
+	}
+}
+
+func (t *tlsHandshakeRecorder) record(cs tls.ConnectionState) {
+	attrs := []attribute.KeyValue{attribute.String("tls.outcome", "failure")}
+	if cs.HandshakeComplete {
+		attrs = []attribute.KeyValue{
+			attribute.String("tls.outcome", "success"),
+			attribute.String("tls.protocol.version", tlsVersionName(cs.Version)),
+			attribute.String("tls.cipher", tls.CipherSuiteName(cs.CipherSuite)),
+		}
+	}
+	tlsHandshakeCnt.Add(context.Background(), 1, metric.WithAttributes(attrs...))
+}
+
+// tlsVersionName은 semconv의 tls.protocol.version 형식("1.2", "1.3")으로 변환합니다.
+func tlsVersionName(v uint16) string {
+	switch v {
+	case tls.VersionTLS10:
+		return "1.0"
+	case tls.VersionTLS11:
+		return "1.1"
+	case tls.VersionTLS12:
+		return "1.2"
+	case tls.VersionTLS13:
+		return "1.3"
+	default:
+		return "unknown"
+	}
+}
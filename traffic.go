@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// demoPlayers는 합성 트래픽이 번갈아 쓰는 플레이어 이름입니다. 빈 값은 익명 플레이어입니다.
+var demoPlayers = []string{"alice", "bob", "carol", ""}
+
+// generateTraffic은 ctx가 끝날 때까지 interval마다 baseURL의 /rolldice를 호출해
+// 대시보드를 채울 텔레메트리를 만듭니다. 계측된 클라이언트를 쓰므로 클라이언트 스팬부터
+// 서버 스팬, 주사위 스팬까지 이어진 트레이스가 남습니다.
+//
+// debug가 켜져 있으면 모든 요청을 디버그 추적으로 표시해 표본 추출 설정과 관계없이 기록합니다.
+//
+// 자기 자신만 호출하므로 TLS를 켠 경우 인증서를 검증하지 않습니다.
+func generateTraffic(ctx context.Context, baseURL string, interval time.Duration, phases, debug bool) {
+	client := newInstrumentedClient(true, phases)
+	if debug {
+		ctx = withDebugTrace(ctx)
+	}
+	log.Printf("%v마다 %s로 합성 트래픽을 보냅니다", interval, baseURL)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		player := demoPlayers[rand.Intn(len(demoPlayers))]
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/rolldice/"+url.PathEscape(player), nil)
+		if err != nil {
+			log.Printf("합성 요청 생성 실패: %v", err)
+			return
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("합성 요청 실패: %v", err)
+			}
+			continue
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
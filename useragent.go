@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// userAgentMatcher는 User-Agent에 agents 중 하나가 (대소문자 구분 없이) 들어 있는지
+// 보고하는 함수를 만듭니다.
+func userAgentMatcher(agents []string) func(*http.Request) bool {
+	lower := make([]string, 0, len(agents))
+	for _, a := range agents {
+		if a = strings.ToLower(strings.TrimSpace(a)); a != "" {
+			lower = append(lower, a)
+		}
+	}
+	return func(r *http.Request) bool {
+		ua := strings.ToLower(r.UserAgent())
+		for _, a := range lower {
+			if strings.Contains(ua, a) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+type suppressTracingKey struct{}
+
+// suppressTracingFor는 match에 걸린 요청의 컨텍스트에 추적 억제 표시를 남깁니다.
+// otelhttp.WithFilter는 서버 스팬만 건너뛰므로, 핸들러 안에서 만드는 자식 스팬(예: roll)이
+// 새 루트 트레이스가 되지 않도록 suppressingSampler가 이 표시를 보고 버립니다.
+// HTTP 계측보다 바깥에 둬야 합니다.
+func suppressTracingFor(match func(*http.Request) bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if match(r) {
+			r = r.WithContext(context.WithValue(r.Context(), suppressTracingKey{}, true))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// suppressingSampler는 추적 억제 표시가 있는 컨텍스트의 스팬을 기록하지 않고,
+// 나머지는 next에 맡깁니다.
+type suppressingSampler struct {
+	next trace.Sampler
+}
+
+func (s suppressingSampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	if suppressed, _ := p.ParentContext.Value(suppressTracingKey{}).(bool); suppressed {
+		return trace.SamplingResult{Decision: trace.Drop}
+	}
+	return s.next.ShouldSample(p)
+}
+
+func (s suppressingSampler) Description() string {
+	return "SuppressingSampler{" + s.next.Description() + "}"
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSkipUserAgents(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.SkipUserAgents = []string{"Scraper", " healthbot "}
+	h := newTestHandler(cfg)
+	tests := []struct {
+		userAgent  string
+		wantServer bool
+	}{
+		{"curl/8.0", true},
+		// 대소문자와 앞뒤 공백은 무시하고 부분 문자열로 찾습니다.
+		{"Mozilla/5.0 (compatible; MyScraper/1.0)", false},
+		{"HealthBot/2", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.userAgent, func(t *testing.T) {
+			resetTelemetry(t)
+			before := histogramCount(t, "http.server.duration")
+			r := httptest.NewRequest(http.MethodGet, "/rolldice/", nil)
+			r.Header.Set("User-Agent", tt.userAgent)
+			if w := serve(h, r); w.Code != http.StatusOK {
+				t.Fatalf("상태 = %d, want 200", w.Code)
+			}
+
+			var server bool
+			for _, s := range endedSpans() {
+				if s.Name == "GET /rolldice/" {
+					server = true
+				}
+			}
+			if server != tt.wantServer {
+				t.Errorf("서버 스팬 있음 = %v, want %v", server, tt.wantServer)
+			}
+			// 건너뛴 요청은 HTTP 메트릭도 남기지 않습니다.
+			wantRequests := uint64(0)
+			if tt.wantServer {
+				wantRequests = 1
+			}
+			if got := histogramCount(t, "http.server.duration") - before; got != wantRequests {
+				t.Errorf("http.server.duration 기록 증가량 = %d, want %d", got, wantRequests)
+			}
+		})
+	}
+}
+
+func TestSuppressingSampler(t *testing.T) {
+	// 억제 표시가 있으면 핸들러 안의 자식 스팬도 새 루트 트레이스로 남지 않습니다.
+	tp, exp := newRecordingTracerProvider(t, nil, sdktrace.WithSampler(suppressingSampler{sdktrace.AlwaysSample()}))
+	h := suppressTracingFor(userAgentMatcher([]string{"scraper"}), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, span := tp.Tracer("test").Start(r.Context(), "roll")
+		span.End()
+	}))
+	tests := []struct {
+		userAgent string
+		wantSpans int
+	}{
+		{"curl/8.0", 1},
+		{"scraper/1.0", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.userAgent, func(t *testing.T) {
+			exp.Reset()
+			r := httptest.NewRequest(http.MethodGet, "/rolldice/", nil)
+			r.Header.Set("User-Agent", tt.userAgent)
+			serve(h, r)
+			if got := len(exp.GetSpans()); got != tt.wantSpans {
+				t.Errorf("스팬 %d개, want %d", got, tt.wantSpans)
+			}
+		})
+	}
+
+	// 표시가 없는 컨텍스트는 감싼 표본 추출기를 따릅니다.
+	tp, exp = newRecordingTracerProvider(t, nil, sdktrace.WithSampler(suppressingSampler{sdktrace.NeverSample()}))
+	_, span := tp.Tracer("test").Start(context.Background(), "root")
+	span.End()
+	if got := len(exp.GetSpans()); got != 0 {
+		t.Errorf("NeverSample을 감쌌는데 스팬 %d개", got)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// versus는 플레이어의 주사위와 상대의 주사위를 비교합니다. 상대의 주사위는 계측된
+// 클라이언트로 versusURL의 /rolldice/를 호출해 얻으므로(기본값은 자기 자신), 한 트레이스에
+// 서버 스팬, versus 스팬, 클라이언트 스팬, 상대 쪽 서버 스팬과 roll 스팬이 이어집니다.
+// traceparent와 배기지 헤더는 전역 전파기가 클라이언트 요청에 싣습니다.
+//
+// 상대 호출이 실패하면 502를 반환합니다.
+func (h *diceHandler) versus(w http.ResponseWriter, r *http.Request) {
+	player, err := h.player(r)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if player == "" {
+		player = anonymousPlayer
+	}
+
+	ctx, span := startSpan(r.Context(), "versus")
+	defer span.End()
+
+	roll := h.roll(ctx)
+	opponent, err := h.opponentRoll(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.ErrorContext(ctx, "상대의 주사위를 받지 못했습니다", "player", player, "error", err)
+		writeProblem(w, r, http.StatusBadGateway, "상대의 주사위를 받지 못했습니다")
+		return
+	}
+
+	winner := "draw"
+	switch {
+	case roll > opponent:
+		winner = player
+	case roll < opponent:
+		winner = "opponent"
+	}
+	// 플레이어의 주사위는 /rolldice와 같은 속성으로 셉니다. 상대의 주사위는 상대 쪽 서버가 셉니다.
+	attrs := []attribute.KeyValue{attribute.Int("roll.value", roll), attribute.String("player", player)}
+	span.SetAttributes(attrs...)
+	span.SetAttributes(
+		attribute.Int("versus.opponent.value", opponent),
+		attribute.String("versus.winner", winner))
+	h.inst.rolls.Add(ctx, 1, metric.WithAttributes(attrs...), metric.WithAttributes(h.baggageAttributes(ctx)...))
+	rollStats.add(roll)
+	logger.InfoContext(ctx, fmt.Sprintf("%s님이 상대와 주사위를 겨뤘습니다", player),
+		"player", player, "roll.value", roll, "versus.opponent.value", opponent, "versus.winner", winner)
+
+	if _, err := fmt.Fprintf(w, "%s: %d\nopponent: %d\nwinner: %s\n", player, roll, opponent, winner); err != nil {
+		logger.ErrorContext(ctx, "응답 쓰기 실패", "error", err)
+	}
+}
+
+// opponentRoll은 versusURL의 /rolldice/를 호출해 상대의 주사위 값을 받습니다.
+func (h *diceHandler) opponentRoll(ctx context.Context) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.versusURL+"/rolldice/", nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64))
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("상대 응답 상태 %s", resp.Status)
+	}
+	return strconv.Atoi(strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+)
+
+// viewSpec은 메트릭 View 파일의 항목 하나입니다. 파일은 이 항목들의 JSON 배열입니다.
+//
+//	[
+//	  {"instrument": "dice.roll.duration", "boundaries": [0.0001, 0.001, 0.01]},
+//	  {"instrument": "dice.rolls", "attributes": ["roll.value"]},
+//	  {"instrument": "http.server.*", "scope": "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp", "drop": true},
+//	  {"instrument": "dice.rolls", "name": "dice.throws"},
+//	  {"instrument": "http.server.duration", "description": "앱 서버 요청 처리 시간"}
+//	]
+//
+// 한 계측기에 여러 항목이 일치하면 파일에서 먼저 나온 항목 하나만 적용됩니다.
+type viewSpec struct {
+	// Instrument는 일치시킬 계측기 이름입니다. "*"와 "?" 와일드카드를 쓸 수 있습니다.
+	Instrument string `json:"instrument"`
+	// Scope가 있으면 이 계측 범위의 계측기만 일치합니다.
+	Scope string `json:"scope"`
+
+	// Name은 스트림의 새 이름입니다. 와일드카드 항목에는 쓸 수 없습니다.
+	Name string `json:"name"`
+	// Description은 스트림의 새 설명입니다. Prometheus 엔드포인트의 HELP 줄로 보입니다.
+	Description string `json:"description"`
+	// Boundaries가 있으면 이 경계로 히스토그램을 집계합니다. 오름차순이어야 합니다.
+	Boundaries []float64 `json:"boundaries"`
+	// Attributes가 있으면 이 키의 속성만 남깁니다.
+	Attributes []string `json:"attributes"`
+	// Drop이 켜져 있으면 계측기를 집계하지 않고 버립니다.
+	Drop bool `json:"drop"`
+}
+
+// loadViews는 path의 View 파일을 읽어 파일 순서대로 View를 만듭니다.
+// 알 수 없는 필드나 잘못된 항목은 몇 번째 항목인지와 함께 모두 보고합니다.
+func loadViews(path string) ([]metric.View, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	var specs []viewSpec
+	if err := dec.Decode(&specs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var (
+		views []metric.View
+		errs  []error
+	)
+	for i, spec := range specs {
+		if err := spec.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %d번째 항목(%q): %w", path, i+1, spec.Instrument, err))
+			continue
+		}
+		views = append(views, spec.view())
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+func (s viewSpec) validate() error {
+	if s.Instrument == "" {
+		return errors.New("instrument가 필요합니다")
+	}
+	if s.Name != "" && strings.ContainsAny(s.Instrument, "*?") {
+		return errors.New("와일드카드 항목에는 name을 쓸 수 없습니다")
+	}
+	if s.Drop && (s.Name != "" || s.Description != "" || s.Boundaries != nil || s.Attributes != nil) {
+		return errors.New("drop은 다른 설정과 함께 쓸 수 없습니다")
+	}
+	for i := 1; i < len(s.Boundaries); i++ {
+		if s.Boundaries[i] <= s.Boundaries[i-1] {
+			return fmt.Errorf("boundaries는 오름차순이어야 합니다: %v", s.Boundaries)
+		}
+	}
+	return nil
+}
+
+func (s viewSpec) view() metric.View {
+	criteria := metric.Instrument{Name: s.Instrument}
+	if s.Scope != "" {
+		criteria.Scope = instrumentation.Scope{Name: s.Scope}
+	}
+	var stream metric.Stream
+	stream.Name = s.Name
+	stream.Description = s.Description
+	switch {
+	case s.Drop:
+		stream.Aggregation = metric.AggregationDrop{}
+	case s.Boundaries != nil:
+		stream.Aggregation = metric.AggregationExplicitBucketHistogram{Boundaries: s.Boundaries}
+	}
+	if s.Attributes != nil {
+		keys := make([]attribute.Key, len(s.Attributes))
+		for i, k := range s.Attributes {
+			keys[i] = attribute.Key(k)
+		}
+		stream.AttributeFilter = attribute.NewAllowKeysFilter(keys...)
+	}
+	return metric.NewView(criteria, stream)
+}
+
+// combinedView는 파일의 View와 히스토그램 예시 저장소 설정을 하나의 View로 합칩니다.
+// 한 계측기에 View가 여럿 일치하면 스트림이 중복되므로 모든 View 설정은 이 View 하나에 모읍니다.
+// 바꿀 것이 없으면 false를 반환합니다.
+//
+// 파일의 View는 순서대로 확인해 처음 일치하는 것 하나만 적용합니다.
+//
+// reservoirSize가 0보다 크면 버킷마다 하나씩 두는 SDK 기본 저장소 대신, 속성 조합마다
+// 최대 reservoirSize개의 예시(exemplar)를 무작위로 남기는 고정 크기 저장소를 히스토그램에 씁니다.
+// 예시마다 값, 시각, 트레이스/스팬 ID와 걸러진 속성을 보관하므로 메모리는 대략
+// 히스토그램 수 × 속성 조합 수 × reservoirSize에 비례해 늘어납니다.
+func combinedView(views []metric.View, reservoirSize int) (metric.View, bool) {
+	if len(views) == 0 && reservoirSize <= 0 {
+		return nil, false
+	}
+	views = slices.Clone(views)
+	return func(i metric.Instrument) (metric.Stream, bool) {
+		var (
+			stream  metric.Stream
+			matched bool
+		)
+		for _, v := range views {
+			if stream, matched = v(i); matched {
+				break
+			}
+		}
+		if reservoirSize <= 0 || i.Kind != metric.InstrumentKindHistogram {
+			return stream, matched
+		}
+		if !matched {
+			stream = metric.Stream{Name: i.Name, Description: i.Description, Unit: i.Unit}
+		}
+		stream.ExemplarReservoirProviderSelector = func(metric.Aggregation) exemplar.ReservoirProvider {
+			return exemplar.FixedSizeReservoirProvider(reservoirSize)
+		}
+		return stream, true
+	}, true
+}